@@ -1,5 +1,10 @@
 package definitions
 
+import (
+	"github.com/alacrity-engine/core/math/geometry"
+	"github.com/alacrity-engine/core/render"
+)
+
 type BatchDefinition struct {
 	Name      string
 	CanvasID  string
@@ -12,3 +17,23 @@ type CanvasDefinition struct {
 	Name  string
 	DrawZ int
 }
+
+type ShaderDefinition struct {
+	Type   render.ShaderType
+	Source string
+}
+
+type ShaderProgramDefinition struct {
+	VertexShaderID   string
+	FragmentShaderID string
+}
+
+type SpritesheetDefinition struct {
+	TextureID string
+	Frames    []SpritesheetFrameDefinition
+}
+
+type SpritesheetFrameDefinition struct {
+	Name string
+	Area geometry.Rect
+}
@@ -1,5 +1,7 @@
 package definitions
 
+import "encoding/gob"
+
 const (
 	ResourceTypeAnimation     = "animation"
 	ResourceTypeAudio         = "audio"
@@ -14,13 +16,39 @@ const (
 // TODO: create a shader and
 // shader program packer.
 
+// GameObjectPointer refers to the game object
+// either by its name or by its packed handle.
+// The handle is used only if the name is empty.
+//
+// The handle is valid only while the game is
+// running, so it's not encoded with gob.
 type GameObjectPointer struct {
-	Name string
+	Name   string
+	handle uint64
+}
+
+// Handle returns the packed handle
+// of the game object.
+func (ptr GameObjectPointer) Handle() uint64 {
+	return ptr.handle
 }
 
+// GameObjectPointerFromHandle creates a new pointer
+// referring to the game object by its packed handle.
+func GameObjectPointerFromHandle(handle uint64) GameObjectPointer {
+	return GameObjectPointer{
+		handle: handle,
+	}
+}
+
+// ComponentPointer refers to the component of
+// the game object. If the game object has several
+// components of the type, Index selects one of them
+// in the order they were added.
 type ComponentPointer struct {
 	GmobName string
 	CompType string
+	Index    int
 }
 
 type ResourcePointer struct {
@@ -32,3 +60,13 @@ type BatchPointer struct {
 	CanvasID string
 	BatchID  string
 }
+
+func init() {
+	// Pointers are stored in component data
+	// as interface{} values so gob must know
+	// their concrete types.
+	gob.Register(GameObjectPointer{})
+	gob.Register(ComponentPointer{})
+	gob.Register(ResourcePointer{})
+	gob.Register(BatchPointer{})
+}
@@ -24,11 +24,15 @@ type GameObjectDefinition struct {
 	Components []*ComponentDefinition
 	Sprite     *SpriteDefinition
 	Draw       bool
+	Active     bool
+	Tags       []string
+	Layer      uint32
 }
 
 type ComponentDefinition struct {
 	TypeName string
 	Active   bool
+	Priority int
 	Data     map[string]interface{}
 }
 
@@ -40,6 +44,8 @@ type SpriteDefinition struct {
 	ColorDrawMode   render.DrawMode
 	ShaderProgramID string
 	TextureID       string
+	SpritesheetID   string
+	FrameName       string
 	CanvasID        string
 	BatchID         string
 }
@@ -0,0 +1,6 @@
+package definitions
+
+type SceneDefinition struct {
+	Name       string
+	Transforms []*TransformDefinition
+}
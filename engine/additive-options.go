@@ -0,0 +1,36 @@
+package engine
+
+// AdditiveSceneOption is an optional
+// parameter of the additive scene.
+type AdditiveSceneOption func(params *additiveSceneParameters) error
+
+// additiveSceneParameters are the optional
+// parameters of the additive scene.
+type additiveSceneParameters struct {
+	updateOrder  int
+	sharedLayout bool
+}
+
+// AdditiveSceneOptionWithUpdateOrder sets the order
+// in which the additive scene is updated relative to
+// the other scenes. The active scene has order 0.
+// Scenes with the same order are updated in the
+// order they were started, after the active one.
+func AdditiveSceneOptionWithUpdateOrder(order int) AdditiveSceneOption {
+	return func(params *additiveSceneParameters) error {
+		params.updateOrder = order
+
+		return nil
+	}
+}
+
+// AdditiveSceneOptionWithSharedLayout makes the
+// additive scene use the draw layout of the active
+// scene instead of its own one.
+func AdditiveSceneOptionWithSharedLayout() AdditiveSceneOption {
+	return func(params *additiveSceneParameters) error {
+		params.sharedLayout = true
+
+		return nil
+	}
+}
@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+)
+
+var (
+	// additiveScenes are the scenes played on
+	// top of the active one sorted by their
+	// update order.
+	additiveScenes []*Scene
+)
+
+// ActiveScene returns the main scene
+// currently being played by the engine.
+func ActiveScene() *Scene {
+	return scenes[currentSceneName]
+}
+
+// CurrentScenes returns the active scene and all
+// the additive scenes in the order of their update.
+func CurrentScenes() []*Scene {
+	current := make([]*Scene, 0, len(additiveScenes)+1)
+	active := ActiveScene()
+	activeAdded := active == nil
+
+	for _, scene := range additiveScenes {
+		if !activeAdded && scene.updateOrder >= 0 {
+			current = append(current, active)
+			activeAdded = true
+		}
+
+		current = append(current, scene)
+	}
+
+	if !activeAdded {
+		current = append(current, active)
+	}
+
+	return current
+}
+
+// StartAdditiveScene starts playing the scene under
+// the specified name on top of the active scene.
+func StartAdditiveScene(sceneName string, options ...AdditiveSceneOption) error {
+	scene, ok := scenes[sceneName]
+
+	if !ok {
+		return NewErrorSceneDoesntExist(sceneName)
+	}
+
+	if scene.additive || sceneName == currentSceneName {
+		return fmt.Errorf("scene '%s' is already being played", sceneName)
+	}
+
+	params := additiveSceneParameters{
+		updateOrder: 1,
+	}
+
+	for i := 0; i < len(options); i++ {
+		option := options[i]
+		err := option(&params)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if params.sharedLayout {
+		active := ActiveScene()
+
+		if active == nil {
+			return fmt.Errorf(
+				"no active scene to share the layout with scene '%s'",
+				sceneName)
+		}
+
+		scene.layout = active.layout
+	}
+
+	err := scene.start()
+
+	if err != nil {
+		scene.layout = scene.ownLayout
+		return err
+	}
+
+	scene.additive = true
+	scene.updateOrder = params.updateOrder
+
+	// Keep the scenes with the same order
+	// in the order they were started.
+	ind := sort.Search(len(additiveScenes), func(i int) bool {
+		return additiveScenes[i].updateOrder > scene.updateOrder
+	})
+	current := make([]*Scene, 0, len(additiveScenes)+1)
+	current = append(current, additiveScenes[:ind]...)
+	current = append(current, scene)
+	current = append(current, additiveScenes[ind:]...)
+	additiveScenes = current
+
+	return nil
+}
+
+// StopAdditiveScene unloads the additive scene
+// under the specified name. Its game objects set
+// to be not destroyed on scene switch are moved
+// to the active scene along with their event
+// subscriptions.
+func StopAdditiveScene(sceneName string) error {
+	scene, ok := scenes[sceneName]
+
+	if !ok {
+		return NewErrorSceneDoesntExist(sceneName)
+	}
+
+	if !scene.additive {
+		return fmt.Errorf("scene '%s' is not additive", sceneName)
+	}
+
+	// The persistent game objects are checked
+	// before the scene is unloaded not to lose
+	// them if they cannot be moved.
+	persistent, err := scene.persistentGameObjects()
+
+	if err != nil {
+		return err
+	}
+
+	active := ActiveScene()
+
+	if len(persistent) > 0 {
+		if active == nil {
+			return fmt.Errorf(
+				"no active scene to move game object '%s' to", persistent[0].name)
+		}
+
+		err = active.checkNamesFree(persistent)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	current := make([]*Scene, 0, len(additiveScenes))
+
+	for _, additiveScene := range additiveScenes {
+		if additiveScene != scene {
+			current = append(current, additiveScene)
+		}
+	}
+
+	additiveScenes = current
+	scene.additive = false
+	scene.updateOrder = 0
+	scene.layout = scene.ownLayout
+
+	persistent, subs, err := scene.unload()
+
+	if err != nil {
+		return err
+	}
+
+	for _, gmob := range persistent {
+		err := active.AddGameObject(gmob, gmob.zUpdate)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, sub := range subs {
+		active.events.subscribe(sub)
+	}
+
+	return nil
+}
+
+// stopAdditiveScenes unloads all the additive scenes.
+func stopAdditiveScenes() error {
+	for len(additiveScenes) > 0 {
+		err := StopAdditiveScene(additiveScenes[0].name)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
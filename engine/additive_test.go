@@ -0,0 +1,279 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// mover is a test component which moves
+// its game object to the other scene.
+type mover struct {
+	probe
+	target *Scene
+}
+
+func (m *mover) Update() error {
+	err := m.probe.Update()
+
+	if err != nil {
+		return err
+	}
+
+	if m.target == nil {
+		return nil
+	}
+
+	target := m.target
+	m.target = nil
+
+	return m.GameObject().Scene().MoveGameObjectTo(
+		m.GameObject().Name(), target)
+}
+
+func TestAdditiveScenes(t *testing.T) {
+	log := []string{}
+	game := newTestScene(t, "game")
+	ui := newTestScene(t, "ui")
+	background := newTestScene(t, "background")
+
+	defer func() {
+		for _, scene := range []*Scene{game, ui, background} {
+			RemoveScene(scene.name)
+		}
+
+		additiveScenes = nil
+		currentSceneName = ""
+	}()
+
+	for _, scene := range []*Scene{game, ui, background} {
+		gmob := NewGameObject(nil, scene.name+"-object", nil)
+		err := gmob.AddComponent(newProbe(scene.name, &log), 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = scene.AddGameObject(gmob, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = AddScene(scene)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := game.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = StartAdditiveScene(ui.name)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = StartAdditiveScene(background.name,
+		AdditiveSceneOptionWithUpdateOrder(-1),
+		AdditiveSceneOptionWithSharedLayout())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(CurrentScenes(), []*Scene{background, game, ui}) {
+		t.Fatal("wrong scene order")
+	}
+
+	if ActiveScene() != game {
+		t.Fatal("wrong active scene")
+	}
+
+	if background.DrawLayout() != game.DrawLayout() || ui.DrawLayout() == game.DrawLayout() {
+		t.Fatal("wrong layouts")
+	}
+
+	// The game object moves itself
+	// to the UI scene during the update.
+	log2 := []string{}
+	movable := NewGameObject(nil, "movable", nil)
+	err = movable.AddComponent(&mover{
+		probe:  *newProbe("movable", &log2),
+		target: ui,
+	}, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = game.AddGameObject(movable, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The child is moved along
+	// with its parent.
+	carried := NewGameObject(movable.Transform(), "carried", nil)
+	err = game.AddGameObject(carried, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := NewManualClock(time.Unix(0, 0))
+	loop, err := NewLoop(RunConfig{
+		Headless: true,
+		Clock:    clock,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		clock.Advance(10 * time.Millisecond)
+		err = loop.Tick()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		"background", "game", "ui",
+		"background", "game", "ui",
+	}
+
+	if !reflect.DeepEqual(log, expected) {
+		t.Fatalf("wrong update order: %v", log)
+	}
+
+	if game.HasGameObject("movable") || ui.FindGameObject("movable") != movable {
+		t.Fatal("the game object is not moved")
+	}
+
+	if game.HasGameObject("carried") || carried.Scene() != ui || carried.Parent() != movable {
+		t.Fatal("the child game object is not moved")
+	}
+
+	if movable.Scene() != ui || len(log2) != 2 {
+		t.Fatalf("the moved game object is not updated properly: %v", log2)
+	}
+
+	err = StopAdditiveScene(background.name)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if background.DrawLayout() == game.DrawLayout() {
+		t.Fatal("the layout is not restored")
+	}
+
+	if !reflect.DeepEqual(CurrentScenes(), []*Scene{game, ui}) {
+		t.Fatal("the additive scene is not stopped")
+	}
+}
+
+func TestFailedMovesKeepGameObjects(t *testing.T) {
+	game := newTestScene(t, "game")
+	hud := newTestScene(t, "hud")
+
+	defer func() {
+		for _, scene := range []*Scene{game, hud} {
+			RemoveScene(scene.name)
+		}
+
+		additiveScenes = nil
+		currentSceneName = ""
+		delete(noDestroyOnSceneSwitch, "cursor")
+	}()
+
+	for _, scene := range []*Scene{game, hud} {
+		err := AddScene(scene)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cursor := NewGameObject(nil, "cursor", nil)
+	err := hud.AddGameObject(cursor, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = hud.DontDestroyOnSceneSwitch("cursor")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = StartAdditiveScene(hud.name)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// There is no active scene
+	// to move the cursor to.
+	err = StopAdditiveScene(hud.name)
+
+	if err == nil {
+		t.Fatal("no error for the absent active scene")
+	}
+
+	if hud.FindGameObject("cursor") != cursor || !hud.additive {
+		t.Fatal("the additive scene is unloaded")
+	}
+
+	err = game.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The game object with the same
+	// name is set to be added.
+	err = game.AddGameObjectInRuntime(NewGameObject(nil, "cursor", nil), 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = StopAdditiveScene(hud.name)
+
+	if err == nil {
+		t.Fatal("no error for the name conflict")
+	}
+
+	if hud.FindGameObject("cursor") != cursor || !hud.additive {
+		t.Fatal("the additive scene is unloaded")
+	}
+
+	// The subtree is not moved partially.
+	pointer := NewGameObject(cursor.Transform(), "pointer", nil)
+	err = hud.AddGameObject(pointer, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = hud.MoveGameObjectTo("cursor", game)
+
+	if err == nil {
+		t.Fatal("no error for the name conflict")
+	}
+
+	if cursor.Scene() != hud || pointer.Scene() != hud {
+		t.Fatal("the subtree is moved partially")
+	}
+}
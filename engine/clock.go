@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock measures the time for the game loop.
+type Clock interface {
+	Now() time.Time
+	Sleep(duration time.Duration)
+}
+
+// systemClock is the clock
+// which measures the real time.
+type systemClock struct{}
+
+// Now returns the current time.
+func (clock systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Sleep pauses the current goroutine
+// for the specified duration.
+func (clock systemClock) Sleep(duration time.Duration) {
+	time.Sleep(duration)
+}
+
+// ManualClock is the clock which time
+// changes only when it's advanced explicitly.
+// It's used to run the game loop deterministically.
+type ManualClock struct {
+	now    time.Time
+	locker *sync.Mutex
+}
+
+// Now returns the current time of the clock.
+func (clock *ManualClock) Now() time.Time {
+	clock.locker.Lock()
+	defer clock.locker.Unlock()
+
+	return clock.now
+}
+
+// Sleep advances the clock
+// by the specified duration.
+func (clock *ManualClock) Sleep(duration time.Duration) {
+	clock.Advance(duration)
+}
+
+// Advance moves the time of the clock
+// forward by the specified duration.
+func (clock *ManualClock) Advance(duration time.Duration) {
+	clock.locker.Lock()
+	defer clock.locker.Unlock()
+
+	clock.now = clock.now.Add(duration)
+}
+
+// NewManualClock creates a new clock
+// which starts at the specified time.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{
+		now:    start,
+		locker: &sync.Mutex{},
+	}
+}
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"reflect"
+
+	"github.com/alacrity-engine/core/math/geometry"
+)
+
+// TransformOwner returns the game object
+// the transform belongs to. If the transform
+// doesn't belong to any game object or the
+// game object was destroyed, nil is returned.
+func TransformOwner(transform *geometry.Transform) *GameObject {
+	owner, _ := transform.Owner().(*GameObject)
+	return owner
+}
+
+// GetComponent returns the first added
+// component of the game object which is
+// of type T.
+func GetComponent[T Component](gmob *GameObject) (T, error) {
+	if comp, ok := findComponentOfType[T](gmob); ok {
+		return comp, nil
+	}
+
+	var zero T
+
+	return zero, RaiseErrorNoComponentOnGameObject(gmob, componentTypeName[T]())
+}
+
+// GetComponentByTypeID returns the first added
+// component of the game object with the specified
+// type ID asserted to type T. If the component is
+// not of type T, ErrorWrongComponentType is returned.
+func GetComponentByTypeID[T Component](gmob *GameObject, typeID string) (T, error) {
+	var zero T
+	comp := gmob.FindComponent(typeID)
+
+	if comp == nil {
+		return zero, RaiseErrorNoComponentOnGameObject(gmob, typeID)
+	}
+
+	typedComp, ok := comp.(T)
+
+	if !ok {
+		return zero, RaiseErrorWrongComponentType(comp,
+			componentTypeName[T](), reflect.TypeOf(comp).String())
+	}
+
+	return typedComp, nil
+}
+
+// GetComponentInChildren returns the first component
+// of type T found on the game object or any of its
+// descendants. The hierarchy is searched breadth-first
+// and children are visited in the order they were attached.
+func GetComponentInChildren[T Component](gmob *GameObject) (T, error) {
+	queue := []*geometry.Transform{gmob.transform}
+
+	for len(queue) > 0 {
+		transform := queue[0]
+		queue = queue[1:]
+
+		// Transforms with no game objects
+		// are traversed but not searched.
+		if owner := TransformOwner(transform); owner != nil {
+			if comp, ok := findComponentOfType[T](owner); ok {
+				return comp, nil
+			}
+		}
+
+		queue = append(queue, transform.Children()...)
+	}
+
+	var zero T
+
+	return zero, RaiseErrorNoComponentOnGameObject(gmob, componentTypeName[T]())
+}
+
+// GetComponentInParent returns the first component
+// of type T found on the game object or any of its
+// ancestors, starting from the game object itself.
+func GetComponentInParent[T Component](gmob *GameObject) (T, error) {
+	for transform := gmob.transform; transform != nil; transform = transform.Parent() {
+		if owner := TransformOwner(transform); owner != nil {
+			if comp, ok := findComponentOfType[T](owner); ok {
+				return comp, nil
+			}
+		}
+	}
+
+	var zero T
+
+	return zero, RaiseErrorNoComponentOnGameObject(gmob, componentTypeName[T]())
+}
+
+// findComponentOfType returns the first
+// added component of the game object
+// which is of type T.
+func findComponentOfType[T Component](gmob *GameObject) (T, bool) {
+	for _, entry := range gmob.additionOrder {
+		if comp, ok := entry.comp.(T); ok {
+			return comp, true
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// componentTypeName returns the name
+// of the component type for the errors.
+func componentTypeName[T Component]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
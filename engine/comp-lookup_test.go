@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/alacrity-engine/core/math/geometry"
+)
+
+func TestGetComponent(t *testing.T) {
+	log := []string{}
+	gmob := NewGameObject(nil, "gmob", nil)
+	prb := newProbe(testCounterTypeID, &log)
+	cnt := &counter{}
+
+	for _, comp := range []Component{prb, cnt} {
+		err := gmob.AddComponent(comp, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := GetComponent[*counter](gmob)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found != cnt {
+		t.Fatal("wrong component found")
+	}
+
+	// The probe pretends to be a counter.
+	_, err = GetComponentByTypeID[*counter](gmob, testCounterTypeID)
+
+	if _, ok := err.(*ErrorWrongComponentType); !ok {
+		t.Fatalf("expected a wrong component type error, got %v", err)
+	}
+
+	_, err = GetComponent[DrawableComponent](gmob)
+
+	if _, ok := err.(*ErrorNoComponentOnGameObject); !ok {
+		t.Fatalf("expected a no component error, got %v", err)
+	}
+
+	// The first added component is found
+	// regardless of the execution priority.
+	early := &counter{}
+	err = gmob.AddComponent(early, -1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found, _ := GetComponent[*counter](gmob); found != cnt {
+		t.Fatal("the component with the lower priority is found")
+	}
+
+	err = gmob.RemoveComponent(cnt)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found, _ := GetComponent[*counter](gmob); found != early {
+		t.Fatal("the removed component is found")
+	}
+}
+
+func TestGetComponentInHierarchy(t *testing.T) {
+	root := NewGameObject(nil, "root", nil)
+	middle := NewGameObject(root.Transform(), "middle", nil)
+	leaf := NewGameObject(middle.Transform(), "leaf", nil)
+	cnt := &counter{}
+
+	err := leaf.AddComponent(cnt, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := GetComponentInChildren[*counter](root)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found != cnt {
+		t.Fatal("wrong component found in children")
+	}
+
+	found, err = GetComponentInParent[*counter](leaf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found != cnt {
+		t.Fatal("the game object itself is not searched")
+	}
+
+	_, err = GetComponentInParent[*counter](middle)
+
+	if err == nil {
+		t.Fatal("the component is found in the parents")
+	}
+
+	if TransformOwner(middle.Transform()) != middle {
+		t.Fatal("wrong transform owner")
+	}
+
+	if TransformOwner(geometry.NewTransform(middle.Transform())) != nil {
+		t.Fatal("the bare transform has an owner")
+	}
+}
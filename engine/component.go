@@ -70,3 +70,41 @@ func (bc *BaseComponent) Update() error {
 func (bc *BaseComponent) Destroy() error {
 	return nil
 }
+
+// EnableHandlerComponent is a component which
+// is notified when it becomes active.
+type EnableHandlerComponent interface {
+	Component
+	OnEnable() error
+}
+
+// DisableHandlerComponent is a component which
+// is notified when it becomes inactive.
+type DisableHandlerComponent interface {
+	Component
+	OnDisable() error
+}
+
+// LateUpdatableComponent is a component
+// which is updated after all the game objects
+// of the scene are updated.
+type LateUpdatableComponent interface {
+	Component
+	LateUpdate() error
+}
+
+// FixedUpdatableComponent is a component
+// which is updated with the fixed timestep
+// of the scene independently of the frame rate.
+type FixedUpdatableComponent interface {
+	Component
+	FixedUpdate() error
+}
+
+// Resettable is a component which restores
+// its initial state when its game object
+// is rented from the object pool.
+type Resettable interface {
+	Component
+	Reset() error
+}
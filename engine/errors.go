@@ -243,3 +243,188 @@ func NewErrorObjectAlreadyNotDestroyedOnSceneSwitch(gmob *GameObject) *ErrorObje
 		gmob: gmob,
 	}
 }
+
+/*****************************************************************************************************************/
+
+// ErrorComponentTypeNotRegistered is returned
+// when the component type is absent in the
+// component type registry.
+type ErrorComponentTypeNotRegistered struct {
+	typeName string
+}
+
+// TypeName returns the name of the
+// unregistered component type.
+func (err *ErrorComponentTypeNotRegistered) TypeName() string {
+	return err.typeName
+}
+
+// Error returns the error message.
+func (err *ErrorComponentTypeNotRegistered) Error() string {
+	return fmt.Sprintf(
+		"component type '%s' is not registered", err.typeName)
+}
+
+// NewErrorComponentTypeNotRegistered creates a new
+// error that is returned when the component type is
+// absent in the component type registry.
+func NewErrorComponentTypeNotRegistered(typeName string) *ErrorComponentTypeNotRegistered {
+	return &ErrorComponentTypeNotRegistered{
+		typeName: typeName,
+	}
+}
+
+/*****************************************************************************************************************/
+
+// ErrorNoComponentTypeField is returned
+// when the registered component type has
+// no field with the specified name.
+type ErrorNoComponentTypeField struct {
+	typeName  string
+	fieldName string
+}
+
+// TypeName returns the name of the component type.
+func (err *ErrorNoComponentTypeField) TypeName() string {
+	return err.typeName
+}
+
+// FieldName returns the name of the absent field.
+func (err *ErrorNoComponentTypeField) FieldName() string {
+	return err.fieldName
+}
+
+// Error returns the error message.
+func (err *ErrorNoComponentTypeField) Error() string {
+	return fmt.Sprintf(
+		"component type '%s' has no accessible field '%s'",
+		err.typeName, err.fieldName)
+}
+
+// NewErrorNoComponentTypeField creates a new
+// error that is returned when the registered
+// component type has no field with the specified name.
+func NewErrorNoComponentTypeField(typeName, fieldName string) *ErrorNoComponentTypeField {
+	return &ErrorNoComponentTypeField{
+		typeName:  typeName,
+		fieldName: fieldName,
+	}
+}
+
+/*****************************************************************************************************************/
+
+// ErrorUnresolvedPointer is returned when
+// the pointer stored in the component field
+// data cannot be resolved.
+type ErrorUnresolvedPointer struct {
+	gmobName  string
+	typeName  string
+	fieldName string
+	pointer   interface{}
+	cause     error
+}
+
+// GameObject returns the name of the game
+// object whose component holds the pointer.
+func (err *ErrorUnresolvedPointer) GameObject() string {
+	return err.gmobName
+}
+
+// TypeName returns the type of the
+// component that holds the pointer.
+func (err *ErrorUnresolvedPointer) TypeName() string {
+	return err.typeName
+}
+
+// FieldName returns the name of the
+// component field that holds the pointer.
+func (err *ErrorUnresolvedPointer) FieldName() string {
+	return err.fieldName
+}
+
+// Pointer returns the unresolved pointer.
+func (err *ErrorUnresolvedPointer) Pointer() interface{} {
+	return err.pointer
+}
+
+// Unwrap returns the reason why the
+// pointer cannot be resolved.
+func (err *ErrorUnresolvedPointer) Unwrap() error {
+	return err.cause
+}
+
+// Error returns the error message.
+func (err *ErrorUnresolvedPointer) Error() string {
+	return fmt.Sprintf(
+		"cannot resolve pointer %+v in field '%s' of component '%s' of game object '%s': %v",
+		err.pointer, err.fieldName, err.typeName, err.gmobName, err.cause)
+}
+
+// NewErrorUnresolvedPointer creates a new error
+// that is returned when the pointer stored in the
+// component field data cannot be resolved.
+func NewErrorUnresolvedPointer(gmobName, typeName, fieldName string, pointer interface{}, cause error) *ErrorUnresolvedPointer {
+	return &ErrorUnresolvedPointer{
+		gmobName:  gmobName,
+		typeName:  typeName,
+		fieldName: fieldName,
+		pointer:   pointer,
+		cause:     cause,
+	}
+}
+
+/*****************************************************************************************************************/
+
+// ErrorStaleHandle is returned when the
+// handle refers to a destroyed game object.
+type ErrorStaleHandle struct {
+	handle Handle
+}
+
+// Handle returns the stale handle.
+func (err *ErrorStaleHandle) Handle() Handle {
+	return err.handle
+}
+
+// Error returns the error message.
+func (err *ErrorStaleHandle) Error() string {
+	return fmt.Sprintf("handle '%s' refers to no game object",
+		err.handle)
+}
+
+// NewErrorStaleHandle returns a new error
+// about the handle referring to no game object.
+func NewErrorStaleHandle(handle Handle) *ErrorStaleHandle {
+	return &ErrorStaleHandle{
+		handle: handle,
+	}
+}
+
+/*****************************************************************************************************************/
+
+// ErrorResourceNotLoaded is returned when
+// the resource was not loaded by any of the
+// scene resource loaders, so it has no ID.
+type ErrorResourceNotLoaded struct {
+	resource interface{}
+}
+
+// Resource returns the resource
+// which has no ID.
+func (err *ErrorResourceNotLoaded) Resource() interface{} {
+	return err.resource
+}
+
+// Error returns the error message.
+func (err *ErrorResourceNotLoaded) Error() string {
+	return fmt.Sprintf("the %T resource was not loaded by the scene",
+		err.resource)
+}
+
+// NewErrorResourceNotLoaded returns a new error
+// about the resource not loaded by the scene.
+func NewErrorResourceNotLoaded(resource interface{}) *ErrorResourceNotLoaded {
+	return &ErrorResourceNotLoaded{
+		resource: resource,
+	}
+}
@@ -0,0 +1,319 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// eventBus delivers the events published
+	// on the scene to their subscribers.
+	eventBus struct {
+		subscriptions map[reflect.Type][]*Subscription
+		subscribers   map[*GameObject][]*Subscription
+		queue         []func() error
+	}
+
+	// Subscription is a handler subscribed
+	// to the events of a certain type.
+	Subscription struct {
+		eventType reflect.Type
+		gmob      *GameObject
+		comp      Component
+		compID    uint64
+		handler   interface{}
+		cancelled bool
+	}
+)
+
+// Unsubscribe stops delivering
+// events to the handler.
+func (sub *Subscription) Unsubscribe() {
+	sub.cancelled = true
+}
+
+// Cancelled returns true if the events are
+// not delivered to the handler anymore.
+func (sub *Subscription) Cancelled() bool {
+	return sub.cancelled || !sub.alive()
+}
+
+// alive returns true if the subscriber
+// of the handler is not destroyed or
+// removed from its game object.
+func (sub *Subscription) alive() bool {
+	if sub.gmob == nil {
+		return true
+	}
+
+	if sub.gmob.destroyed {
+		return false
+	}
+
+	return sub.comp == nil || sub.gmob.componentIDs[sub.compID] == sub.comp
+}
+
+// subscribe adds a new subscription to the bus.
+func (bus *eventBus) subscribe(sub *Subscription) {
+	bus.subscriptions[sub.eventType] = append(
+		bus.subscriptions[sub.eventType], sub)
+
+	if sub.gmob != nil {
+		bus.subscribers[sub.gmob] = append(
+			bus.subscribers[sub.gmob], sub)
+	}
+}
+
+// unsubscribeGameObject cancels all the subscriptions
+// of the game object and its components.
+func (bus *eventBus) unsubscribeGameObject(gmob *GameObject) {
+	for _, sub := range bus.subscribers[gmob] {
+		sub.cancelled = true
+	}
+
+	delete(bus.subscribers, gmob)
+}
+
+// takeGameObject removes all the subscriptions of the
+// game object and its components from the bus and returns
+// the ones not cancelled yet, so they can be moved to the
+// bus of the other scene.
+func (bus *eventBus) takeGameObject(gmob *GameObject) []*Subscription {
+	subs := bus.subscribers[gmob]
+
+	if len(subs) == 0 {
+		return nil
+	}
+
+	delete(bus.subscribers, gmob)
+	taken := make([]*Subscription, 0, len(subs))
+
+	for _, sub := range subs {
+		if !sub.Cancelled() {
+			taken = append(taken, sub)
+		}
+	}
+
+	for eventType, typeSubs := range bus.subscriptions {
+		kept := make([]*Subscription, 0, len(typeSubs))
+
+		for _, sub := range typeSubs {
+			if sub.gmob != gmob {
+				kept = append(kept, sub)
+			}
+		}
+
+		if len(kept) > 0 {
+			bus.subscriptions[eventType] = kept
+		} else {
+			delete(bus.subscriptions, eventType)
+		}
+	}
+
+	return taken
+}
+
+// unsubscribeComponent cancels all the
+// subscriptions of the game object component.
+func (bus *eventBus) unsubscribeComponent(gmob *GameObject, compID uint64) {
+	subs := bus.subscribers[gmob]
+	kept := make([]*Subscription, 0, len(subs))
+
+	for _, sub := range subs {
+		if sub.comp != nil && sub.compID == compID {
+			sub.cancelled = true
+			continue
+		}
+
+		kept = append(kept, sub)
+	}
+
+	if len(kept) > 0 {
+		bus.subscribers[gmob] = kept
+	} else {
+		delete(bus.subscribers, gmob)
+	}
+}
+
+// activeSubscriptions drops all the cancelled
+// subscriptions to the events of the type and
+// returns the rest of them.
+func (bus *eventBus) activeSubscriptions(eventType reflect.Type) []*Subscription {
+	subs := bus.subscriptions[eventType]
+	active := make([]*Subscription, 0, len(subs))
+
+	for _, sub := range subs {
+		if !sub.Cancelled() {
+			active = append(active, sub)
+		}
+	}
+
+	if len(active) > 0 {
+		bus.subscriptions[eventType] = active
+	} else {
+		delete(bus.subscriptions, eventType)
+	}
+
+	return active
+}
+
+// deliverQueuedEvents delivers all the events
+// queued before the call. The events queued by
+// the handlers are delivered on the next call.
+func (bus *eventBus) deliverQueuedEvents() error {
+	queue := bus.queue
+	bus.queue = []func() error{}
+
+	for _, deliver := range queue {
+		err := deliver()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eventTypeOf returns the type of the events
+// delivered to the handlers of the type parameter.
+func eventTypeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// publish delivers the event to all the
+// subscribers of its type in the order
+// they were subscribed.
+func publish[T any](bus *eventBus, event T) error {
+	// The handlers subscribed during the
+	// delivery don't receive the event.
+	for _, sub := range bus.activeSubscriptions(eventTypeOf[T]()) {
+		if sub.Cancelled() {
+			continue
+		}
+
+		err := sub.handler.(func(event T) error)(event)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Subscribe makes the handler receive all the
+// events of the type published on the scene.
+// The subscription is cancelled when the component
+// is removed from its game object or the game object
+// is destroyed or removed from the scene. When the game
+// object is moved to the other scene, the handler
+// receives the events of that scene. If the component
+// is nil, the subscription lasts until it's cancelled
+// explicitly or the scene is unloaded.
+//
+// The handlers receive the events in
+// the order they were subscribed.
+func Subscribe[T any](scene *Scene, subscriber Component, handler func(event T) error) (*Subscription, error) {
+	if scene == nil {
+		return nil, fmt.Errorf("the scene is nil")
+	}
+
+	if handler == nil {
+		return nil, fmt.Errorf("the handler is nil")
+	}
+
+	sub := &Subscription{
+		eventType: eventTypeOf[T](),
+		handler:   handler,
+	}
+
+	if subscriber != nil {
+		gmob := subscriber.GameObject()
+
+		if gmob == nil {
+			return nil, fmt.Errorf("the subscriber is not attached to a game object")
+		}
+
+		compID, ok := gmob.ComponentID(subscriber)
+
+		if !ok {
+			return nil, fmt.Errorf("the subscriber is not a component of game object '%s'",
+				gmob.name)
+		}
+
+		sub.gmob = gmob
+		sub.comp = subscriber
+		sub.compID = compID
+	}
+
+	scene.events.subscribe(sub)
+
+	return sub, nil
+}
+
+// SubscribeGameObject makes the handler receive
+// all the events of the type published on the
+// scene until the game object is destroyed or
+// removed from the scene. The subscription follows
+// the game object moved to the other scene.
+func SubscribeGameObject[T any](scene *Scene, subscriber *GameObject, handler func(event T) error) (*Subscription, error) {
+	if scene == nil {
+		return nil, fmt.Errorf("the scene is nil")
+	}
+
+	if subscriber == nil {
+		return nil, fmt.Errorf("the subscriber is nil")
+	}
+
+	if handler == nil {
+		return nil, fmt.Errorf("the handler is nil")
+	}
+
+	sub := &Subscription{
+		eventType: eventTypeOf[T](),
+		gmob:      subscriber,
+		handler:   handler,
+	}
+	scene.events.subscribe(sub)
+
+	return sub, nil
+}
+
+// Publish immediately delivers the event to
+// all the handlers subscribed to its type.
+func Publish[T any](scene *Scene, event T) error {
+	if scene == nil {
+		return fmt.Errorf("the scene is nil")
+	}
+
+	return publish(scene.events, event)
+}
+
+// Queue defers the delivery of the event until
+// the next update of the scene. The queued events
+// are delivered after the buffered game objects are
+// added to the scene and before the game objects are
+// updated, in the order they were queued.
+func Queue[T any](scene *Scene, event T) error {
+	if scene == nil {
+		return fmt.Errorf("the scene is nil")
+	}
+
+	bus := scene.events
+	bus.queue = append(bus.queue, func() error {
+		return publish(bus, event)
+	})
+
+	return nil
+}
+
+// newEventBus creates a new
+// empty bus for the scene events.
+func newEventBus() *eventBus {
+	return &eventBus{
+		subscriptions: map[reflect.Type][]*Subscription{},
+		subscribers:   map[*GameObject][]*Subscription{},
+		queue:         []func() error{},
+	}
+}
@@ -0,0 +1,222 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type hitEvent struct {
+	damage int
+}
+
+func TestEventBusDelivery(t *testing.T) {
+	log := []string{}
+	scene := newTestScene(t, "events")
+	gmobs := []*GameObject{}
+
+	for _, name := range []string{"first", "second"} {
+		gmob := NewGameObject(nil, name, nil)
+		err := gmob.AddComponent(newProbe(name, &[]string{}), 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = scene.AddGameObject(gmob, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gmobs = append(gmobs, gmob)
+	}
+
+	record := func(name string) func(event hitEvent) error {
+		return func(event hitEvent) error {
+			log = append(log, fmt.Sprintf("%s:%d", name, event.damage))
+			return nil
+		}
+	}
+
+	_, err := Subscribe(scene, gmobs[1].FindComponent("second"), record("second"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = SubscribeGameObject(scene, gmobs[0], record("first"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	global, err := Subscribe[hitEvent](scene, nil, func(event hitEvent) error {
+		log = append(log, fmt.Sprintf("global:%d", event.damage))
+
+		// The events queued during the delivery
+		// are delivered on the next update.
+		if event.damage < 3 {
+			return Queue(scene, hitEvent{damage: event.damage + 1})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Publish(scene, hitEvent{damage: 1})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(log, []string{"second:1", "first:1", "global:1"}) {
+		t.Fatalf("wrong delivery order: %v", log)
+	}
+
+	log = []string{}
+	err = Publish(scene, "other event type")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log) > 0 {
+		t.Fatalf("the event is delivered to the wrong handlers: %v", log)
+	}
+
+	// The subscriptions are cancelled when
+	// the subscribers are removed or destroyed.
+	err = gmobs[1].RemoveComponent(gmobs[1].FindComponent("second"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(log, []string{"first:2", "global:2"}) {
+		t.Fatalf("wrong queued delivery: %v", log)
+	}
+
+	log = []string{}
+	err = scene.DestroyGameObject("first")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(log, []string{"global:3"}) {
+		t.Fatalf("wrong delivery after destruction: %v", log)
+	}
+
+	global.Unsubscribe()
+	log = []string{}
+	err = Publish(scene, hitEvent{damage: 4})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log) > 0 || len(scene.events.subscriptions) > 0 {
+		t.Fatalf("the cancelled subscriptions are not dropped: %v", log)
+	}
+}
+
+func TestEventSubscriptionsFollowGameObject(t *testing.T) {
+	log := []string{}
+	from := newTestScene(t, "from")
+	to := newTestScene(t, "to")
+	parent := NewGameObject(nil, "parent", nil)
+	child := NewGameObject(parent.Transform(), "child", nil)
+	err := child.AddComponent(newProbe("child", &[]string{}), 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, gmob := range []*GameObject{parent, child} {
+		err := from.AddGameObject(gmob, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	record := func(name string) func(event hitEvent) error {
+		return func(event hitEvent) error {
+			log = append(log, fmt.Sprintf("%s:%d", name, event.damage))
+			return nil
+		}
+	}
+
+	_, err = SubscribeGameObject(from, parent, record("parent"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Subscribe(from, child.FindComponent("child"), record("child"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = from.MoveGameObjectTo("parent", to)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Publish(from, hitEvent{damage: 1})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Publish(to, hitEvent{damage: 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(log, []string{"parent:2", "child:2"}) {
+		t.Fatalf("wrong delivery after the move: %v", log)
+	}
+
+	if len(from.events.subscriptions) > 0 || len(from.events.subscribers) > 0 {
+		t.Fatal("the old scene keeps the subscriptions")
+	}
+
+	// The subscriptions of the game object
+	// removed from the scene are cancelled.
+	log = []string{}
+	err = to.RemoveGameObject("child")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Publish(to, hitEvent{damage: 3})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(log, []string{"parent:3"}) {
+		t.Fatalf("wrong delivery after the removal: %v", log)
+	}
+}
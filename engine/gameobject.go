@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/alacrity-engine/core/math/geometry"
 	"github.com/alacrity-engine/core/render"
@@ -11,21 +12,44 @@ import (
 // gameobject to inherit a sprite
 // from another game object by link.
 
-// GameObject represents a single object
-// in the game world which contains components
-// to be updated once per frame.
-type GameObject struct {
-	name          string
-	components    map[string]Component
-	drawComponent DrawableComponent
-	transform     *geometry.Transform
-	sprite        *render.Sprite
-	scene         *Scene
-	draw          bool
-	destroyed     bool
-	zUpdate       float32
-	started       bool
-}
+type (
+	// GameObject represents a single object
+	// in the game world which contains components
+	// to be updated once per frame.
+	GameObject struct {
+		name           string
+		handle         Handle
+		components     map[string][]Component
+		componentIDs   map[uint64]Component
+		componentOrder []prioritizedComponent
+		additionOrder  []prioritizedComponent
+		activeStates   map[uint64]bool
+		lastCompID     uint64
+		tags           map[string]struct{}
+		layer          LayerMask
+		drawComponent  DrawableComponent
+		transform      *geometry.Transform
+		sprite         *render.Sprite
+		resources      []ownedResource
+		scene          *Scene
+		draw           bool
+		drawSet        bool
+		active         bool
+		destroyed      bool
+		zUpdate        float32
+		updateSeq      uint64
+		started        bool
+	}
+
+	// prioritizedComponent is a component
+	// along with its instance ID and
+	// execution priority.
+	prioritizedComponent struct {
+		comp     Component
+		id       uint64
+		priority int
+	}
+)
 
 // Destroyed returns true if the game object
 // has been destroyed and must not be drawn
@@ -35,14 +59,166 @@ func (gmob *GameObject) Destroyed() bool {
 }
 
 // SetDraw sets if the game object should be drawn.
+// The flag of the game object is kept as it is when
+// its ancestors are hidden, so the game object is
+// drawn again once they're shown. The flag set before
+// the game object is started is kept by the start.
 func (gmob *GameObject) SetDraw(draw bool) {
 	gmob.draw = draw
+	gmob.drawSet = true
+}
+
+// DrawSelf returns the draw flag of the game
+// object regardless of its ancestors.
+func (gmob *GameObject) DrawSelf() bool {
+	return gmob.draw
 }
 
 // ShouldBeDrawn returns true if the game object
-// should be drawn, and false otherwise.
+// and all its ancestors are set to be drawn and
+// active, and false otherwise.
 func (gmob *GameObject) ShouldBeDrawn() bool {
-	return gmob.draw
+	for transform := gmob.transform; transform != nil; transform = transform.Parent() {
+		if owner := TransformOwner(transform); owner != nil &&
+			(!owner.draw || !owner.active) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Handle returns the generational handle of the
+// game object. The handle is assigned when the game
+// object is placed on a scene and becomes stale when
+// the game object is destroyed or removed from the
+// scene, so the handle of the game object on no
+// scene is nil.
+func (gmob *GameObject) Handle() Handle {
+	return gmob.handle
+}
+
+// Active returns the activity flag of the
+// game object regardless of its ancestors.
+func (gmob *GameObject) Active() bool {
+	return gmob.active
+}
+
+// ActiveInHierarchy returns true if the game
+// object and all its ancestors are active, i.e.
+// the components of the game object are updated.
+func (gmob *GameObject) ActiveInHierarchy() bool {
+	for transform := gmob.transform; transform != nil; transform = transform.Parent() {
+		if owner := TransformOwner(transform); owner != nil && !owner.active {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetActive sets if the game object should be updated
+// and drawn. The descendants are updated and drawn only
+// if the game object is active, but their own flags are
+// kept as they are.
+//
+// The components of the started game objects are
+// enabled or disabled immediately. The components
+// of the game objects not started yet are enabled
+// when the game objects are started.
+func (gmob *GameObject) SetActive(active bool) error {
+	gmob.active = active
+
+	return gmob.syncSubtreeActiveStates()
+}
+
+// syncSubtreeActiveStates notifies the components
+// of the game object and all its descendants whose
+// activity status has changed.
+func (gmob *GameObject) syncSubtreeActiveStates() error {
+	err := gmob.syncActiveStates()
+
+	if err != nil {
+		return err
+	}
+
+	for _, child := range gmob.Children() {
+		err := child.syncSubtreeActiveStates()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Parent returns the game object owning
+// the parent transform of the game object.
+func (gmob *GameObject) Parent() *GameObject {
+	parent := gmob.transform.Parent()
+
+	if parent == nil {
+		return nil
+	}
+
+	return TransformOwner(parent)
+}
+
+// SetParent makes the game object a child of the
+// parent game object. If the parent is nil, the game
+// object is detached from its current parent.
+func (gmob *GameObject) SetParent(parent *GameObject) error {
+	var parentTransform *geometry.Transform
+
+	if parent != nil {
+		if gmob.destroyed {
+			return fmt.Errorf("game object '%s' is destroyed", gmob.name)
+		}
+
+		if parent.destroyed {
+			return fmt.Errorf("game object '%s' is destroyed", parent.name)
+		}
+
+		parentTransform = parent.transform
+	}
+
+	err := gmob.transform.SetParent(parentTransform)
+
+	if err != nil {
+		return err
+	}
+
+	// The new ancestors may
+	// be active or not.
+	return gmob.syncSubtreeActiveStates()
+}
+
+// Children returns the game objects owning the
+// child transforms of the game object in the order
+// they were attached.
+func (gmob *GameObject) Children() []*GameObject {
+	children := []*GameObject{}
+
+	for _, child := range gmob.transform.Children() {
+		if owner := TransformOwner(child); owner != nil {
+			children = append(children, owner)
+		}
+	}
+
+	return children
+}
+
+// Root returns the topmost ancestor of the game
+// object or the game object itself if it has no parent.
+func (gmob *GameObject) Root() *GameObject {
+	root := gmob
+
+	for parent := root.Parent(); parent != nil; parent = root.Parent() {
+		root = parent
+	}
+
+	return root
 }
 
 // Name returns the name of the game object.
@@ -50,6 +226,58 @@ func (gmob *GameObject) Name() string {
 	return gmob.name
 }
 
+// Tags returns all the tags of the
+// game object in the alphabetical order.
+func (gmob *GameObject) Tags() []string {
+	tags := make([]string, 0, len(gmob.tags))
+
+	for tag := range gmob.tags {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
+
+// HasTag returns true if the game
+// object has the specified tag.
+func (gmob *GameObject) HasTag(tag string) bool {
+	_, ok := gmob.tags[tag]
+
+	return ok
+}
+
+// AddTag adds the tag to the game object.
+// Adding an existing tag has no effect.
+func (gmob *GameObject) AddTag(tag string) {
+	gmob.tags[tag] = struct{}{}
+
+	if gmob.scene != nil {
+		gmob.scene.indexTag(gmob, tag)
+	}
+}
+
+// RemoveTag removes the tag from the game object.
+// Removing an absent tag has no effect.
+func (gmob *GameObject) RemoveTag(tag string) {
+	delete(gmob.tags, tag)
+
+	if gmob.scene != nil {
+		gmob.scene.unindexTag(gmob, tag)
+	}
+}
+
+// Layer returns the layer mask of the game object.
+func (gmob *GameObject) Layer() LayerMask {
+	return gmob.layer
+}
+
+// SetLayer sets the layer mask of the game object.
+func (gmob *GameObject) SetLayer(layer LayerMask) {
+	gmob.layer = layer
+}
+
 // Transform returns the transform to perform
 // affine transformations on the game object.
 func (gmob *GameObject) Transform() *geometry.Transform {
@@ -57,21 +285,37 @@ func (gmob *GameObject) Transform() *geometry.Transform {
 }
 
 // Start starts all the components
-// of the game object.
+// of the game object. Active components
+// are enabled before they are started.
+// The game object is set to be drawn unless
+// its draw flag was set before the start.
 func (gmob *GameObject) Start() error {
 	if gmob.started {
 		return nil
 	}
 
-	for _, comp := range gmob.components {
-		err := comp.Start()
+	for _, entry := range gmob.componentOrder {
+		if gmob.removed(entry) {
+			continue
+		}
+
+		err := gmob.syncActiveState(entry)
 
 		if err != nil {
 			return err
 		}
+
+		err = entry.comp.Start()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if !gmob.drawSet {
+		gmob.draw = true
 	}
 
-	gmob.draw = true
 	gmob.started = true
 
 	return nil
@@ -80,9 +324,71 @@ func (gmob *GameObject) Start() error {
 // Update calls update method on all
 // game object components.
 func (gmob *GameObject) Update() error {
-	for _, comp := range gmob.components {
-		if comp.Active() {
-			err := comp.Update()
+	for _, entry := range gmob.componentOrder {
+		if gmob.removed(entry) {
+			continue
+		}
+
+		err := gmob.syncActiveState(entry)
+
+		if err != nil {
+			return err
+		}
+
+		if gmob.componentActive(entry.comp) {
+			err := entry.comp.Update()
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// LateUpdate calls late update method on all
+// game object components that have it.
+func (gmob *GameObject) LateUpdate() error {
+	for _, entry := range gmob.componentOrder {
+		if gmob.removed(entry) {
+			continue
+		}
+
+		err := gmob.syncActiveState(entry)
+
+		if err != nil {
+			return err
+		}
+
+		if comp, ok := entry.comp.(LateUpdatableComponent); ok && gmob.componentActive(comp) {
+			err := comp.LateUpdate()
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// FixedUpdate calls fixed update method on all
+// game object components that have it.
+func (gmob *GameObject) FixedUpdate() error {
+	for _, entry := range gmob.componentOrder {
+		if gmob.removed(entry) {
+			continue
+		}
+
+		err := gmob.syncActiveState(entry)
+
+		if err != nil {
+			return err
+		}
+
+		if comp, ok := entry.comp.(FixedUpdatableComponent); ok && gmob.componentActive(comp) {
+			err := comp.FixedUpdate()
 
 			if err != nil {
 				return err
@@ -93,6 +399,86 @@ func (gmob *GameObject) Update() error {
 	return nil
 }
 
+// syncActiveState notifies the component if
+// its activity status has changed since the
+// last time it was checked.
+func (gmob *GameObject) syncActiveState(entry prioritizedComponent) error {
+	active := gmob.componentActive(entry.comp)
+
+	if gmob.activeStates[entry.id] == active {
+		return nil
+	}
+
+	gmob.activeStates[entry.id] = active
+
+	if active {
+		if comp, ok := entry.comp.(EnableHandlerComponent); ok {
+			return comp.OnEnable()
+		}
+
+		return nil
+	}
+
+	if comp, ok := entry.comp.(DisableHandlerComponent); ok {
+		return comp.OnDisable()
+	}
+
+	return nil
+}
+
+// syncActiveStates notifies all the components
+// of the started game object whose activity status
+// has changed since the last time it was checked.
+func (gmob *GameObject) syncActiveStates() error {
+	if !gmob.started || gmob.destroyed {
+		return nil
+	}
+
+	for _, entry := range gmob.componentOrder {
+		if gmob.removed(entry) {
+			continue
+		}
+
+		err := gmob.syncActiveState(entry)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removed returns true if the component was
+// removed from the game object while the
+// components were being iterated over.
+func (gmob *GameObject) removed(entry prioritizedComponent) bool {
+	_, ok := gmob.componentIDs[entry.id]
+	return !ok
+}
+
+// componentActive returns true if both the component
+// and the game object are active in the hierarchy.
+func (gmob *GameObject) componentActive(comp Component) bool {
+	return comp.Active() && gmob.ActiveInHierarchy()
+}
+
+// disableComponent notifies the component
+// that it is disabled if it was enabled.
+func (gmob *GameObject) disableComponent(entry prioritizedComponent) error {
+	if !gmob.activeStates[entry.id] {
+		return nil
+	}
+
+	gmob.activeStates[entry.id] = false
+
+	if comp, ok := entry.comp.(DisableHandlerComponent); ok {
+		return comp.OnDisable()
+	}
+
+	return nil
+}
+
 // Sprite returns the graphical sprite of the
 // game object.
 func (gmob *GameObject) Sprite() *render.Sprite {
@@ -105,12 +491,17 @@ func (gmob *GameObject) SetSprite(sprite *render.Sprite) {
 }
 
 // Draw the game object onto the target.
+// Inactive game objects are not drawn.
 func (gmob *GameObject) Draw() error {
-	if gmob.draw && gmob.drawComponent != nil {
+	if !gmob.ShouldBeDrawn() {
+		return nil
+	}
+
+	if gmob.drawComponent != nil {
 		return gmob.drawComponent.Draw(gmob.transform)
 	}
 
-	if gmob.draw && gmob.sprite != nil {
+	if gmob.sprite != nil {
 		return gmob.sprite.Draw(gmob.transform)
 	}
 
@@ -138,9 +529,44 @@ func (gmob *GameObject) ReclaimDrawing() error {
 }
 
 // FindComponent searches for the component with the specified name
-// and returns it if it exists.
+// and returns it if it exists. If the game object has several
+// components of the type, the one added first is returned.
 func (gmob *GameObject) FindComponent(name string) Component {
-	return gmob.components[name]
+	components := gmob.components[name]
+
+	if len(components) <= 0 {
+		return nil
+	}
+
+	return components[0]
+}
+
+// FindComponents returns all the components of the
+// specified type in the order they were added.
+func (gmob *GameObject) FindComponents(name string) []Component {
+	components := make([]Component, len(gmob.components[name]))
+	copy(components, gmob.components[name])
+
+	return components
+}
+
+// FindComponentByID returns the component
+// with the specified instance ID.
+func (gmob *GameObject) FindComponentByID(id uint64) Component {
+	return gmob.componentIDs[id]
+}
+
+// ComponentID returns the instance ID of
+// the game object component. The ID stays the
+// same until the component is removed.
+func (gmob *GameObject) ComponentID(component Component) (uint64, bool) {
+	for _, entry := range gmob.componentOrder {
+		if entry.comp == component {
+			return entry.id, true
+		}
+	}
+
+	return 0, false
 }
 
 // Scene returns the scene where the game object
@@ -162,7 +588,39 @@ func (gmob *GameObject) HasComponent(name string) bool {
 	return component != nil
 }
 
+// Components returns all the components of the
+// game object in the order of their execution.
+func (gmob *GameObject) Components() []Component {
+	components := make([]Component, 0, len(gmob.componentOrder))
+
+	for _, entry := range gmob.componentOrder {
+		components = append(components, entry.comp)
+	}
+
+	return components
+}
+
+// ComponentPriority returns the execution
+// priority of the game object component.
+func (gmob *GameObject) ComponentPriority(component Component) (int, bool) {
+	for _, entry := range gmob.componentOrder {
+		if entry.comp == component {
+			return entry.priority, true
+		}
+	}
+
+	return 0, false
+}
+
 // AddComponent adds the component in the game object.
+// The game object can have several components of the same
+// type, each of them gets its own instance ID.
+//
+// Components with lower priority values are started
+// and updated earlier. Components with the same priority
+// are executed in the order they were added.
+// The component attached to another game object
+// must be removed from it first.
 func (gmob *GameObject) AddComponent(component Component, priority int) error {
 	regComp, ok := component.(RegisteredComponent)
 
@@ -170,17 +628,47 @@ func (gmob *GameObject) AddComponent(component Component, priority int) error {
 		return fmt.Errorf("the component can't be registered")
 	}
 
-	typeID := regComp.TypeID()
+	if _, ok := gmob.ComponentID(component); ok {
+		return fmt.Errorf(
+			"the component is already added to the game object '%s'",
+			gmob.name)
+	}
 
-	if _, ok := gmob.components[typeID]; ok {
+	if owner := component.GameObject(); owner != nil {
 		return fmt.Errorf(
-			"the game object '%s' already has a '%s' component",
-			gmob.name, typeID)
+			"the component is already attached to the game object '%s'",
+			owner.name)
 	}
 
-	gmob.components[typeID] = component
+	typeID := regComp.TypeID()
+	gmob.lastCompID++
+	id := gmob.lastCompID
+
+	// Insert the component after all the
+	// components with the same priority.
+	ind := sort.Search(len(gmob.componentOrder), func(i int) bool {
+		return gmob.componentOrder[i].priority > priority
+	})
+	order := make([]prioritizedComponent, 0, len(gmob.componentOrder)+1)
+	order = append(order, gmob.componentOrder[:ind]...)
+	entry := prioritizedComponent{
+		comp:     component,
+		id:       id,
+		priority: priority,
+	}
+	order = append(order, entry)
+	order = append(order, gmob.componentOrder[ind:]...)
+
+	gmob.componentOrder = order
+	gmob.additionOrder = append(gmob.additionOrder, entry)
+	gmob.components[typeID] = append(gmob.components[typeID], component)
+	gmob.componentIDs[id] = component
 	component.SetGameObject(gmob)
 
+	if gmob.scene != nil {
+		gmob.scene.indexComponent(gmob, typeID)
+	}
+
 	return nil
 }
 
@@ -193,17 +681,90 @@ func (gmob *GameObject) RemoveComponent(component Component) error {
 		return fmt.Errorf("the component can't be registered")
 	}
 
-	if !gmob.HasComponent(regComp.TypeID()) {
+	id, ok := gmob.ComponentID(component)
+
+	if !ok {
 		return RaiseErrorNoComponentOnGameObject(gmob, regComp.TypeID())
 	}
 
-	err := component.Destroy()
+	return gmob.removeComponent(regComp, id)
+}
+
+// RemoveComponentByID removes the component with
+// the specified instance ID from the game object.
+func (gmob *GameObject) RemoveComponentByID(id uint64) error {
+	component, ok := gmob.componentIDs[id]
+
+	if !ok {
+		return RaiseErrorNoComponentOnGameObject(gmob, fmt.Sprintf("#%d", id))
+	}
+
+	return gmob.removeComponent(component.(RegisteredComponent), id)
+}
+
+// removeComponent destroys the component
+// and detaches it from the game object.
+func (gmob *GameObject) removeComponent(component RegisteredComponent, id uint64) error {
+	err := gmob.disableComponent(prioritizedComponent{
+		comp: component,
+		id:   id,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = component.Destroy()
 
 	if err != nil {
 		return err
 	}
 
-	delete(gmob.components, regComp.TypeID())
+	// The orders are copied for the components
+	// being iterated over to stay intact.
+	order := make([]prioritizedComponent, 0, len(gmob.componentOrder))
+
+	for _, entry := range gmob.componentOrder {
+		if entry.id != id {
+			order = append(order, entry)
+		}
+	}
+
+	additionOrder := make([]prioritizedComponent, 0, len(gmob.additionOrder))
+
+	for _, entry := range gmob.additionOrder {
+		if entry.id != id {
+			additionOrder = append(additionOrder, entry)
+		}
+	}
+
+	typeID := component.TypeID()
+	sameType := make([]Component, 0, len(gmob.components[typeID]))
+
+	for _, comp := range gmob.components[typeID] {
+		if comp != component {
+			sameType = append(sameType, comp)
+		}
+	}
+
+	if len(sameType) > 0 {
+		gmob.components[typeID] = sameType
+	} else {
+		delete(gmob.components, typeID)
+
+		if gmob.scene != nil {
+			gmob.scene.unindexComponent(gmob, typeID)
+		}
+	}
+
+	if gmob.scene != nil {
+		gmob.scene.events.unsubscribeComponent(gmob, id)
+	}
+
+	gmob.componentOrder = order
+	gmob.additionOrder = additionOrder
+	delete(gmob.componentIDs, id)
+	delete(gmob.activeStates, id)
 	component.SetGameObject(nil)
 
 	return nil
@@ -212,16 +773,25 @@ func (gmob *GameObject) RemoveComponent(component Component) error {
 // ComponentCount returns the number of components
 // of the game object.
 func (gmob *GameObject) ComponentCount() int {
-	return len(gmob.components)
+	return len(gmob.componentOrder)
 }
 
 // NewGameObject creates a new game object with no components.
 func NewGameObject(parent *geometry.Transform, name string, sprite *render.Sprite) *GameObject {
-	return &GameObject{
-		name:       name,
-		components: map[string]Component{},
-		transform:  geometry.NewTransform(parent),
-		sprite:     sprite,
-		draw:       false,
+	gmob := &GameObject{
+		name:           name,
+		components:     map[string][]Component{},
+		componentIDs:   map[uint64]Component{},
+		componentOrder: []prioritizedComponent{},
+		additionOrder:  []prioritizedComponent{},
+		activeStates:   map[uint64]bool{},
+		tags:           map[string]struct{}{},
+		transform:      geometry.NewTransform(parent),
+		sprite:         sprite,
+		draw:           false,
+		active:         true,
 	}
+	gmob.transform.SetOwner(gmob)
+
+	return gmob
 }
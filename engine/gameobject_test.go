@@ -0,0 +1,648 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alacrity-engine/core/system"
+)
+
+// probe is a test component that records
+// its updates into the shared log.
+type probe struct {
+	BaseComponent
+	typeID string
+	log    *[]string
+}
+
+func (p *probe) TypeID() string {
+	return p.typeID
+}
+
+func (p *probe) Update() error {
+	*p.log = append(*p.log, p.typeID)
+	return nil
+}
+
+func newProbe(typeID string, log *[]string) *probe {
+	p := &probe{
+		typeID: typeID,
+		log:    log,
+	}
+	p.SetActive(true)
+
+	return p
+}
+
+func TestComponentPriorityOrder(t *testing.T) {
+	log := []string{}
+	gmob := NewGameObject(nil, "gmob", nil)
+
+	for _, entry := range []struct {
+		typeID   string
+		priority int
+	}{
+		{"late", 10},
+		{"first-default", 0},
+		{"early", -5},
+		{"second-default", 0},
+	} {
+		err := gmob.AddComponent(newProbe(entry.typeID, &log), entry.priority)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		err := gmob.Update()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		"early", "first-default", "second-default", "late",
+		"early", "first-default", "second-default", "late",
+	}
+
+	if !reflect.DeepEqual(log, expected) {
+		t.Fatalf("wrong update order: %v", log)
+	}
+}
+
+func TestSceneUpdateOrderIsInsertionOrder(t *testing.T) {
+	log := []string{}
+	scene := newTestScene(t, "order")
+	names := []string{"e", "b", "d", "a", "c"}
+
+	for _, name := range names {
+		gmob := NewGameObject(nil, name, nil)
+		err := gmob.AddComponent(newProbe(name, &log), 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = scene.AddGameObject(gmob, 1)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		log = log[:0]
+		err := scene.Update()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(log, names) {
+			t.Fatalf("wrong update order: %v", log)
+		}
+	}
+}
+
+func TestMultipleComponentsOfSameType(t *testing.T) {
+	log := []string{}
+	gmob := NewGameObject(nil, "gmob", nil)
+	first := newProbe("hitbox", &log)
+	second := newProbe("hitbox", &log)
+
+	for _, comp := range []*probe{first, second} {
+		err := gmob.AddComponent(comp, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := gmob.AddComponent(first, 0); err == nil {
+		t.Fatal("the same component is added twice")
+	}
+
+	other := NewGameObject(nil, "other", nil)
+
+	if err := other.AddComponent(first, 0); err == nil {
+		t.Fatal("the component is added to another game object")
+	}
+
+	if first.GameObject() != gmob || other.ComponentCount() != 0 {
+		t.Fatal("the component is moved to another game object")
+	}
+
+	if gmob.FindComponent("hitbox") != first {
+		t.Fatal("the first component is not found")
+	}
+
+	if components := gmob.FindComponents("hitbox"); len(components) != 2 ||
+		components[0] != first || components[1] != second {
+		t.Fatalf("wrong components: %v", components)
+	}
+
+	firstID, _ := gmob.ComponentID(first)
+	secondID, _ := gmob.ComponentID(second)
+
+	if firstID == secondID {
+		t.Fatal("the components have the same instance ID")
+	}
+
+	err := gmob.RemoveComponentByID(firstID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gmob.FindComponent("hitbox") != second {
+		t.Fatal("the remaining component is not found")
+	}
+
+	if id, _ := gmob.ComponentID(second); id != secondID {
+		t.Fatal("the instance ID has changed")
+	}
+
+	if err := gmob.RemoveComponentByID(firstID); err == nil {
+		t.Fatal("the component is removed twice")
+	}
+}
+
+// lifecycleProbe is a test component that
+// records all its lifecycle hooks.
+type lifecycleProbe struct {
+	probe
+}
+
+func (p *lifecycleProbe) OnEnable() error {
+	*p.log = append(*p.log, "enable")
+	return nil
+}
+
+func (p *lifecycleProbe) OnDisable() error {
+	*p.log = append(*p.log, "disable")
+	return nil
+}
+
+func (p *lifecycleProbe) Start() error {
+	*p.log = append(*p.log, "start")
+	return nil
+}
+
+func (p *lifecycleProbe) Update() error {
+	*p.log = append(*p.log, "update")
+	return nil
+}
+
+func (p *lifecycleProbe) LateUpdate() error {
+	*p.log = append(*p.log, "late")
+	return nil
+}
+
+func (p *lifecycleProbe) FixedUpdate() error {
+	*p.log = append(*p.log, "fixed")
+	return nil
+}
+
+func TestComponentLifecycleHooks(t *testing.T) {
+	// Scene.Update accumulates the
+	// system delta time for fixed updates.
+	system.SetDeltaTime(0)
+
+	log := []string{}
+	scene := newTestScene(t, "lifecycle")
+	gmob := NewGameObject(nil, "gmob", nil)
+	comp := &lifecycleProbe{probe: *newProbe("lifecycle", &log)}
+
+	err := gmob.AddComponent(comp, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.AddGameObject(gmob, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.SetFixedTimestep(0.1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two fixed steps fit into the time.
+	err = scene.fixedUpdate(0.25)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comp.SetActive(false)
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The accumulated time is enough
+	// for one more fixed step.
+	comp.SetActive(true)
+	system.SetDeltaTime(0.06)
+	t.Cleanup(func() {
+		system.SetDeltaTime(0)
+	})
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"enable", "start",
+		"fixed", "fixed",
+		"update", "late",
+		"disable",
+		"enable", "fixed", "update", "late",
+	}
+
+	if !reflect.DeepEqual(log, expected) {
+		t.Fatalf("wrong lifecycle: %v", log)
+	}
+
+	if scene.fixedAccumulator < 0.009 || scene.fixedAccumulator > 0.011 {
+		t.Fatalf("wrong accumulated time: %f", scene.fixedAccumulator)
+	}
+
+	// The game object notifies its
+	// components as soon as it's deactivated.
+	log = log[:0]
+	err = gmob.SetActive(false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = gmob.SetActive(true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(log, []string{"disable", "enable"}) {
+		t.Fatalf("wrong activity notifications: %v", log)
+	}
+
+	// The components of the game object not
+	// started yet are enabled on start only.
+	log = log[:0]
+	idle := NewGameObject(nil, "idle", nil)
+	err = idle.AddComponent(&lifecycleProbe{probe: *newProbe("lifecycle", &log)}, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, active := range []bool{false, true} {
+		err = idle.SetActive(active)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(log) != 0 {
+		t.Fatalf("the components are notified before start: %v", log)
+	}
+
+	err = idle.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(log, []string{"enable", "start"}) {
+		t.Fatalf("wrong start notifications: %v", log)
+	}
+}
+
+func TestGameObjectHierarchy(t *testing.T) {
+	log := []string{}
+	scene := newTestScene(t, "hierarchy")
+	gmobs := map[string]*GameObject{}
+
+	for _, name := range []string{"root", "arm", "hand", "leg"} {
+		gmob := NewGameObject(nil, name, nil)
+		err := gmob.AddComponent(newProbe(name, &log), 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = scene.AddGameObject(gmob, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gmobs[name] = gmob
+	}
+
+	for _, link := range [][2]string{
+		{"arm", "root"},
+		{"hand", "arm"},
+		{"leg", "root"},
+	} {
+		err := gmobs[link[0]].SetParent(gmobs[link[1]])
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if names := gameObjectNames(gmobs["root"].Children()); !reflect.DeepEqual(names, []string{"arm", "leg"}) {
+		t.Fatalf("wrong children: %v", names)
+	}
+
+	if gmobs["hand"].Root() != gmobs["root"] || gmobs["root"].Root() != gmobs["root"] {
+		t.Fatal("wrong root")
+	}
+
+	if gmobs["hand"].Transform().Parent() != gmobs["arm"].Transform() {
+		t.Fatal("the transform tree is out of sync")
+	}
+
+	err := gmobs["root"].SetParent(gmobs["hand"])
+
+	if err == nil {
+		t.Fatal("no error for the parenting cycle")
+	}
+
+	// Reparenting detaches the game
+	// object from its previous parent.
+	err = gmobs["leg"].SetParent(gmobs["arm"])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := gameObjectNames(gmobs["root"].Children()); !reflect.DeepEqual(names, []string{"arm"}) {
+		t.Fatalf("the game object is not detached from its parent: %v", names)
+	}
+
+	err = gmobs["arm"].SetActive(false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gmobs["root"].SetDraw(false)
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(log, []string{"root"}) {
+		t.Fatalf("the inactive subtree is updated: %v", log)
+	}
+
+	for _, gmob := range gmobs {
+		if gmob.ShouldBeDrawn() {
+			t.Fatalf("game object '%s' is drawn", gmob.Name())
+		}
+	}
+
+	// The own flags of the descendants are
+	// kept and take effect once the ancestors
+	// are activated and shown again.
+	if !gmobs["hand"].Active() || gmobs["hand"].ActiveInHierarchy() {
+		t.Fatal("wrong activity of the descendant")
+	}
+
+	gmobs["hand"].SetDraw(true)
+	err = gmobs["leg"].SetActive(false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = gmobs["arm"].SetActive(true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gmobs["hand"].ShouldBeDrawn() {
+		t.Fatal("the descendant of the hidden game object is drawn")
+	}
+
+	gmobs["root"].SetDraw(true)
+	gmobs["arm"].SetDraw(true)
+
+	if !gmobs["hand"].ShouldBeDrawn() || !gmobs["hand"].ActiveInHierarchy() {
+		t.Fatal("the descendant is not restored")
+	}
+
+	if gmobs["leg"].Active() || gmobs["leg"].ActiveInHierarchy() {
+		t.Fatal("the flag of the descendant is overwritten")
+	}
+
+	err = gmobs["arm"].SetActive(false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.DestroyGameObject("arm")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"arm", "hand", "leg"} {
+		if !gmobs[name].Destroyed() {
+			t.Fatalf("game object '%s' is not destroyed", name)
+		}
+	}
+
+	if len(gmobs["root"].Children()) != 0 {
+		t.Fatal("the destroyed child is not detached")
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"arm", "hand", "leg"} {
+		if scene.HasGameObject(name) {
+			t.Fatalf("game object '%s' is not removed", name)
+		}
+	}
+}
+
+func TestMovedGameObjectIsUpdatedLast(t *testing.T) {
+	log := []string{}
+	scene := newTestScene(t, "order")
+	other := newTestScene(t, "other")
+	gmobs := map[string]*GameObject{}
+
+	for _, name := range []string{"moved", "resident"} {
+		gmob := NewGameObject(nil, name, nil)
+		err := gmob.AddComponent(newProbe(name, &log), 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gmobs[name] = gmob
+	}
+
+	err := scene.AddGameObject(gmobs["moved"], 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = other.AddGameObject(gmobs["resident"], 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The order of insertion into
+	// the other scene is kept.
+	err = scene.MoveGameObjectTo("moved", other)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = other.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(log, []string{"resident", "moved"}) {
+		t.Fatalf("wrong update order: %v", log)
+	}
+}
+
+// remover is a test component which removes
+// the other component on its update.
+type remover struct {
+	BaseComponent
+	target Component
+}
+
+func (r *remover) TypeID() string {
+	return "engine__Remover"
+}
+
+func (r *remover) Update() error {
+	if r.target == nil {
+		return nil
+	}
+
+	err := r.GameObject().RemoveComponent(r.target)
+	r.target = nil
+
+	return err
+}
+
+func TestComponentRemovedDuringUpdate(t *testing.T) {
+	system.SetDeltaTime(0)
+	log := []string{}
+	scene := newTestScene(t, "removal")
+	gmob := NewGameObject(nil, "gmob", nil)
+	target := &lifecycleProbe{probe: *newProbe("lifecycle", &log)}
+	comp := &remover{target: target}
+	comp.SetActive(true)
+
+	err := gmob.AddComponent(comp, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = gmob.AddComponent(target, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.AddGameObject(gmob, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The removed component is disabled
+	// and neither enabled again nor updated.
+	log = log[:0]
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(log, []string{"disable"}) {
+		t.Fatalf("wrong hooks of the removed component: %v", log)
+	}
+}
+
+func TestGameObjectIsDrawnOnStart(t *testing.T) {
+	gmob := NewGameObject(nil, "shown", nil)
+
+	if gmob.DrawSelf() {
+		t.Fatal("the game object is drawn before the start")
+	}
+
+	err := gmob.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !gmob.DrawSelf() {
+		t.Fatal("the game object is not drawn after the start")
+	}
+
+	// The draw flag set before
+	// the start is kept.
+	hidden := NewGameObject(nil, "hidden", nil)
+	hidden.SetDraw(false)
+	err = hidden.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hidden.DrawSelf() {
+		t.Fatal("the start overrides the draw flag")
+	}
+}
@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Handle refers to the game object by its generational
+// ID. The index addresses the slot of the game object
+// and the generation tells apart the game objects that
+// occupied the same slot. When the game object is
+// destroyed, the generation of its slot is incremented,
+// so all the handles to it become stale.
+//
+// The zero value is the nil handle
+// which refers to no game object.
+type Handle struct {
+	index      uint32
+	generation uint32
+}
+
+// handleSlot is a slot of the game
+// object handle table.
+type handleSlot struct {
+	gmob       *GameObject
+	generation uint32
+}
+
+var (
+	// handleLocker guards the slots so the handles
+	// can be checked from any goroutine.
+	handleLocker *sync.RWMutex
+	// handleSlots are the slots of all the
+	// game objects placed on the scenes.
+	handleSlots []handleSlot
+	// freeHandleSlots are the indices of the slots
+	// released by the destroyed and removed game objects.
+	freeHandleSlots []uint32
+	// lastAutoNameID is the number of the last
+	// name generated for a game object.
+	lastAutoNameID uint64
+)
+
+func init() {
+	handleLocker = new(sync.RWMutex)
+	handleSlots = []handleSlot{}
+	freeHandleSlots = []uint32{}
+}
+
+// Index returns the index of the
+// slot the handle refers to.
+func (handle Handle) Index() uint32 {
+	return handle.index
+}
+
+// Generation returns the generation
+// of the slot the handle refers to.
+func (handle Handle) Generation() uint32 {
+	return handle.generation
+}
+
+// IsNil returns true if the
+// handle refers to no game object.
+func (handle Handle) IsNil() bool {
+	return handle.generation == 0
+}
+
+// Valid returns true if the game object
+// the handle refers to is not destroyed.
+func (handle Handle) Valid() bool {
+	return handle.GameObject() != nil
+}
+
+// GameObject returns the game object the handle
+// refers to. If the game object was destroyed
+// or the handle is nil, nil is returned.
+func (handle Handle) GameObject() *GameObject {
+	handleLocker.RLock()
+	defer handleLocker.RUnlock()
+
+	return handle.gameObject()
+}
+
+// gameObject returns the game object
+// the handle refers to with no locking.
+func (handle Handle) gameObject() *GameObject {
+	if handle.IsNil() || int(handle.index) >= len(handleSlots) {
+		return nil
+	}
+
+	slot := handleSlots[handle.index]
+
+	if slot.generation != handle.generation {
+		return nil
+	}
+
+	return slot.gmob
+}
+
+// Uint64 packs the handle into a single number
+// to refer to the game object with a pointer.
+func (handle Handle) Uint64() uint64 {
+	return uint64(handle.generation)<<32 | uint64(handle.index)
+}
+
+// String returns the text
+// representation of the handle.
+func (handle Handle) String() string {
+	return fmt.Sprintf("%d:%d", handle.index, handle.generation)
+}
+
+// HandleFromUint64 unpacks the handle
+// packed with the Uint64 method.
+func HandleFromUint64(value uint64) Handle {
+	return Handle{
+		index:      uint32(value),
+		generation: uint32(value >> 32),
+	}
+}
+
+// allocateHandle places the game object in
+// a free slot and returns the handle to it.
+func allocateHandle(gmob *GameObject) Handle {
+	handleLocker.Lock()
+	defer handleLocker.Unlock()
+
+	if len(freeHandleSlots) > 0 {
+		index := freeHandleSlots[len(freeHandleSlots)-1]
+		freeHandleSlots = freeHandleSlots[:len(freeHandleSlots)-1]
+		handleSlots[index].gmob = gmob
+
+		return Handle{
+			index:      index,
+			generation: handleSlots[index].generation,
+		}
+	}
+
+	handleSlots = append(handleSlots, handleSlot{
+		gmob:       gmob,
+		generation: 1,
+	})
+
+	return Handle{
+		index:      uint32(len(handleSlots) - 1),
+		generation: 1,
+	}
+}
+
+// releaseHandle frees the slot the handle refers
+// to and makes all the handles to it stale.
+func releaseHandle(handle Handle) {
+	handleLocker.Lock()
+	defer handleLocker.Unlock()
+
+	if handle.gameObject() == nil {
+		return
+	}
+
+	slot := &handleSlots[handle.index]
+	slot.gmob = nil
+	slot.generation++
+
+	// Zero generation is
+	// reserved for nil handles.
+	if slot.generation == 0 {
+		slot.generation = 1
+	}
+
+	freeHandleSlots = append(freeHandleSlots, handle.index)
+}
+
+// GenerateGameObjectName returns a new unique name
+// for the game object made of the prefix and the
+// number of the name, e.g. "bullet#17". Generated
+// names are never reused, so a name of a destroyed
+// game object cannot match a newly created one.
+func GenerateGameObjectName(prefix string) string {
+	lastAutoNameID++
+
+	return fmt.Sprintf("%s#%d", prefix, lastAutoNameID)
+}
@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"testing"
+
+	"github.com/alacrity-engine/core/definitions"
+)
+
+func TestHandlesDetectDestroyedGameObjects(t *testing.T) {
+	scene := newTestScene(t, "handles")
+	bullet := NewGameObject(nil, GenerateGameObjectName("bullet"), nil)
+
+	if !strings.HasPrefix(bullet.Name(), "bullet#") {
+		t.Fatalf("wrong generated name: %s", bullet.Name())
+	}
+
+	// The handle is assigned only when
+	// the game object is placed on the scene.
+	if !bullet.Handle().IsNil() {
+		t.Fatal("the game object on no scene has a handle")
+	}
+
+	err := scene.AddGameObject(bullet, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handle := bullet.Handle()
+
+	if handle.IsNil() {
+		t.Fatal("the handle is nil")
+	}
+
+	if HandleFromUint64(handle.Uint64()) != handle {
+		t.Fatal("the packed handle is not unpacked properly")
+	}
+
+	if scene.FindGameObjectByHandle(handle) != bullet {
+		t.Fatal("the game object is not found by its handle")
+	}
+
+	err = scene.DestroyGameObject(bullet.Name())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if handle.Valid() || scene.FindGameObjectByHandle(handle) != nil {
+		t.Fatal("the handle of the destroyed game object is not stale")
+	}
+
+	// The new game object can take the slot
+	// of the destroyed one but not its handle.
+	other := NewGameObject(nil, GenerateGameObjectName("bullet"), nil)
+	err = scene.AddGameObject(other, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if other.Handle() == handle || other.Name() == bullet.Name() {
+		t.Fatal("the handle or the name is reused")
+	}
+
+	if other.Handle().Index() == handle.Index() &&
+		other.Handle().Generation() == handle.Generation() {
+		t.Fatal("the generation is not incremented")
+	}
+
+	// The removed game object
+	// releases its slot as well.
+	handle = other.Handle()
+	err = scene.RemoveGameObject(other.Name())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if handle.Valid() || !other.Handle().IsNil() {
+		t.Fatal("the handle of the removed game object is not stale")
+	}
+}
+
+func TestInstantiatePrefabWithAutoNames(t *testing.T) {
+	scene := newTestScene(t, "auto-names")
+	target := NewGameObject(nil, "target", nil)
+	err := scene.AddGameObject(target, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def := counterDefinition("bullet", 0, "")
+	ptr := definitions.GameObjectPointerFromHandle(target.Handle().Uint64())
+	def.Components[0].Data["Target"] = ptr
+	prefab := &definitions.Prefab{
+		Name:          "bullet",
+		TransformRoot: &definitions.TransformDefinition{Gmob: def},
+	}
+
+	first, err := InstantiatePrefab(scene, prefab, nil,
+		InstantiationOptionWithAutoNames())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := InstantiatePrefab(scene, prefab, nil,
+		InstantiationOptionWithAutoNames())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Name() == second.Name() || !strings.HasPrefix(first.Name(), "bullet#") {
+		t.Fatalf("wrong generated names: %s, %s", first.Name(), second.Name())
+	}
+
+	if first.FindComponent(testCounterTypeID).(*counter).Target != target {
+		t.Fatal("the handle pointer is not resolved")
+	}
+
+	// The handle is valid only while
+	// the game is running.
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(ptr)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded definitions.GameObjectPointer
+	err = gob.NewDecoder(&buf).Decode(&decoded)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Handle() != 0 {
+		t.Fatal("the handle is encoded")
+	}
+
+	err = scene.DestroyGameObject("target")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = InstantiatePrefab(scene, prefab, nil,
+		InstantiationOptionWithAutoNames())
+
+	if unresolved, ok := err.(*ErrorUnresolvedPointer); !ok {
+		t.Fatalf("expected an unresolved pointer error, got %v", err)
+	} else if _, ok := unresolved.Unwrap().(*ErrorStaleHandle); !ok {
+		t.Fatalf("expected a stale handle error, got %v", unresolved.Unwrap())
+	}
+}
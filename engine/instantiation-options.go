@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/alacrity-engine/core/render"
+	"github.com/alacrity-engine/core/resources"
+)
+
+// InstantiationOption changes the way
+// game objects are created out of definitions.
+type InstantiationOption func(params *instantiationParameters) error
+
+// instantiationParameters holds the parameters
+// to be used for instantiation of definitions.
+type instantiationParameters struct {
+	resourceLoader *resources.ResourceLoader
+	shaderProgram  *render.ShaderProgram
+	autoNames      bool
+}
+
+// InstantiationOptionWithResourceLoader sets the resource loader
+// to load textures and other resources required
+// by the definitions.
+func InstantiationOptionWithResourceLoader(loader *resources.ResourceLoader) InstantiationOption {
+	return func(params *instantiationParameters) error {
+		if loader == nil {
+			return fmt.Errorf("the resource loader is nil")
+		}
+
+		params.resourceLoader = loader
+		return nil
+	}
+}
+
+// InstantiationOptionWithShaderProgram sets the shader
+// program for all the sprites created out of the definitions
+// which don't specify a shader program of their own.
+func InstantiationOptionWithShaderProgram(program *render.ShaderProgram) InstantiationOption {
+	return func(params *instantiationParameters) error {
+		if program == nil {
+			return fmt.Errorf("the shader program is nil")
+		}
+
+		params.shaderProgram = program
+		return nil
+	}
+}
+
+// InstantiationOptionWithAutoNames makes all the created
+// game objects have unique generated names with the names
+// from the definitions as prefixes, e.g. "bullet#17".
+// Pointers in the definitions still refer to the game
+// objects by the names from the definitions.
+func InstantiationOptionWithAutoNames() InstantiationOption {
+	return func(params *instantiationParameters) error {
+		params.autoNames = true
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package engine
+
+// LayerMask is a set of layers
+// the game object belongs to.
+// Each bit stands for a single layer.
+type LayerMask uint32
+
+const (
+	// LayerNone is the mask
+	// with no layers set.
+	LayerNone LayerMask = 0
+	// LayerAll is the mask
+	// with all the layers set.
+	LayerAll LayerMask = ^LayerMask(0)
+)
+
+// Layer returns the mask with only
+// the layer with the specified index set.
+func Layer(index uint) LayerMask {
+	return LayerMask(1) << index
+}
+
+// Intersects returns true if the masks
+// have at least one common layer.
+func (mask LayerMask) Intersects(other LayerMask) bool {
+	return mask&other != 0
+}
+
+// Contains returns true if the mask
+// has all the layers of the other mask.
+func (mask LayerMask) Contains(other LayerMask) bool {
+	return mask&other == other
+}
@@ -0,0 +1,42 @@
+package engine
+
+import "fmt"
+
+// SceneLoadingOption is an optional
+// parameter of the scene loading.
+type SceneLoadingOption func(params *sceneLoadingParameters) error
+
+// sceneLoadingParameters are the optional
+// parameters of the scene loading.
+type sceneLoadingParameters struct {
+	setup                func(scene *Scene) error
+	instantiationOptions []InstantiationOption
+}
+
+// SceneLoadingOptionWithSetup sets the function called
+// for the new scene before its game objects are created.
+// It should be used to create canvases and batches the
+// sprites of the scene are placed onto.
+func SceneLoadingOptionWithSetup(setup func(scene *Scene) error) SceneLoadingOption {
+	return func(params *sceneLoadingParameters) error {
+		if setup == nil {
+			return fmt.Errorf("the setup function is nil")
+		}
+
+		params.setup = setup
+
+		return nil
+	}
+}
+
+// SceneLoadingOptionWithInstantiationOptions sets
+// the options to instantiate the scene definition with.
+// The resource loader of the scene file is used by default.
+func SceneLoadingOptionWithInstantiationOptions(options ...InstantiationOption) SceneLoadingOption {
+	return func(params *sceneLoadingParameters) error {
+		params.instantiationOptions = append(
+			params.instantiationOptions, options...)
+
+		return nil
+	}
+}
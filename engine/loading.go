@@ -24,8 +24,9 @@ func init() {
 
 // CurrentScene returns the scene
 // currently being played by the engine.
+// It's the same as ActiveScene.
 func CurrentScene() *Scene {
-	return scenes[currentSceneName]
+	return ActiveScene()
 }
 
 // StartLoadingScene starts an asynchronous
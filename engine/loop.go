@@ -0,0 +1,446 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/alacrity-engine/core/render"
+	"github.com/alacrity-engine/core/resources"
+	"github.com/alacrity-engine/core/system"
+)
+
+const (
+	// defaultMaxFixedSteps is the number of fixed
+	// updates the loop can perform per frame if
+	// no other number is specified.
+	defaultMaxFixedSteps = 5
+)
+
+// RunConfig is the configuration
+// of the game loop.
+type RunConfig struct {
+	// Headless makes the loop work
+	// without the window: the layout is not
+	// drawn and the window events are not polled.
+	Headless bool
+	// MaxFixedSteps is the maximum number of fixed
+	// updates per frame. If the loop falls behind
+	// further, the rest of the time is dropped.
+	// Zero means the default value.
+	MaxFixedSteps int
+	// TargetFPS limits the number of frames per
+	// second. Zero means no limit.
+	TargetFPS int
+	// Clock measures the time for the loop.
+	// If not specified, the real time is used.
+	Clock Clock
+	// Draw is called at the end of each frame
+	// with the interpolation alpha between the
+	// two last fixed updates.
+	Draw func(alpha float64) error
+	// StateHash enables the test mode: after each
+	// updated frame the states of the current scenes
+	// are hashed, and the function is called with
+	// the number of the frame and the hash, so the
+	// divergence of two runs can be detected.
+	StateHash func(frame uint64, hash uint64) error
+	// InputRecorder records the button states
+	// and the delta time of each frame. The engine
+	// random source is reseeded with its seed, so
+	// the loop must be created before the scenes
+	// are started.
+	InputRecorder *system.InputRecorder
+	// InputPlayer plays the recorded input back in
+	// place of the window: the button states and the
+	// delta time are read from the record, and the
+	// engine random source is reseeded with the
+	// recorded seed. The loop stops when the
+	// record is over. As with the recorder, the loop
+	// must be created before the scenes are started.
+	// The window input is restored when the loop stops.
+	InputPlayer *system.InputPlayer
+}
+
+// Loop is the game loop which updates
+// the current scenes with the fixed timestep
+// accumulator and draws them.
+//
+// The fixed timestep of the active
+// scene is used for all the scenes.
+type Loop struct {
+	config        RunConfig
+	clock         Clock
+	lastFrame     time.Time
+	accumulator   float64
+	alpha         float64
+	paused        bool
+	stepRequested bool
+	stopped       bool
+	frame         uint64
+	// inputInstalled is true if the
+	// loop replaced the input source.
+	inputInstalled bool
+}
+
+// Alpha returns the interpolation alpha, i.e.
+// the fraction of the fixed timestep accumulated
+// after the last fixed update. It should be used
+// to interpolate the rendered state between the
+// two last fixed updates.
+func (loop *Loop) Alpha() float64 {
+	return loop.alpha
+}
+
+// Frame returns the number of
+// frames the loop has updated.
+func (loop *Loop) Frame() uint64 {
+	return loop.frame
+}
+
+// Paused returns true if the loop
+// doesn't update the scene.
+func (loop *Loop) Paused() bool {
+	return loop.paused
+}
+
+// Pause stops updating the scene.
+// The scene is still drawn.
+func (loop *Loop) Pause() {
+	loop.paused = true
+}
+
+// Resume continues updating the scene.
+func (loop *Loop) Resume() {
+	loop.paused = false
+	loop.stepRequested = false
+}
+
+// StepFrame makes the paused loop update
+// the scene once on the next tick with
+// exactly one fixed update.
+func (loop *Loop) StepFrame() {
+	loop.stepRequested = true
+}
+
+// Stop makes the loop exit after the current
+// tick. The input source installed by the loop
+// is reset once the tick is over.
+func (loop *Loop) Stop() {
+	loop.stopped = true
+}
+
+// resetInput makes the button states be
+// read from the window again if the loop
+// replaced the input source.
+func (loop *Loop) resetInput() {
+	if loop.inputInstalled {
+		system.SetInputSource(nil)
+		loop.inputInstalled = false
+	}
+}
+
+// Tick performs a single frame of the loop.
+func (loop *Loop) Tick() error {
+	err := loop.tick()
+
+	if loop.stopped {
+		loop.resetInput()
+	}
+
+	return err
+}
+
+// tick updates and draws the current scenes.
+func (loop *Loop) tick() error {
+	active := ActiveScene()
+
+	if active == nil {
+		return fmt.Errorf("no scene is being played")
+	}
+
+	current := CurrentScenes()
+	now := loop.waitForFrame()
+	deltaTime := now.Sub(loop.lastFrame).Seconds()
+	loop.lastFrame = now
+
+	// Upload the resources loaded asynchronously
+	// and complete their loading processes.
+	resources.ProcessMainThreadQueue()
+
+	if loop.config.InputPlayer != nil {
+		recorded, err := loop.config.InputPlayer.NextFrame()
+
+		if err == io.EOF {
+			loop.Stop()
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		deltaTime = recorded
+	}
+
+	err := loop.update(active, current, deltaTime)
+
+	if err != nil {
+		return err
+	}
+
+	if loop.config.InputRecorder != nil {
+		err = loop.config.InputRecorder.EndFrame(deltaTime)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return loop.draw(current)
+}
+
+// update performs the fixed updates and the frame
+// updates of the current scenes unless the loop
+// is paused.
+func (loop *Loop) update(active *Scene, current []*Scene, deltaTime float64) error {
+	timestep := active.FixedTimestep()
+
+	if loop.paused && !loop.stepRequested {
+		system.SetDeltaTime(0)
+		return nil
+	}
+
+	if loop.paused {
+		// Advance the paused loop
+		// by exactly one fixed update.
+		loop.stepRequested = false
+		loop.accumulator = timestep
+		deltaTime = timestep
+	} else {
+		loop.accumulator += deltaTime
+	}
+
+	system.SetDeltaTime(deltaTime)
+
+	for _, scene := range current {
+		err := scene.flushBuffers()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for steps := 0; loop.accumulator >= timestep; steps++ {
+		if steps >= loop.config.MaxFixedSteps {
+			// Drop the time the loop
+			// cannot catch up with.
+			loop.accumulator = math.Mod(loop.accumulator, timestep)
+			break
+		}
+
+		for _, scene := range current {
+			err := scene.fixedStep()
+
+			if err != nil {
+				return err
+			}
+		}
+
+		loop.accumulator -= timestep
+	}
+
+	loop.alpha = loop.accumulator / timestep
+
+	for _, scene := range current {
+		err := scene.frameUpdate()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	loop.frame++
+
+	if loop.config.StateHash != nil {
+		hash, err := hashScenes(current)
+
+		if err != nil {
+			return err
+		}
+
+		err = loop.config.StateHash(loop.frame, hash)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitForFrame waits for the time of the next
+// frame according to the FPS limit and returns
+// the time the frame starts.
+func (loop *Loop) waitForFrame() time.Time {
+	now := loop.clock.Now()
+
+	if loop.config.TargetFPS <= 0 {
+		return now
+	}
+
+	frameDuration := time.Second / time.Duration(loop.config.TargetFPS)
+
+	if elapsed := now.Sub(loop.lastFrame); elapsed < frameDuration {
+		loop.clock.Sleep(frameDuration - elapsed)
+		now = loop.clock.Now()
+	}
+
+	return now
+}
+
+// draw draws the game objects of the scenes
+// and finishes the frame. The layouts shared
+// by several scenes are drawn once.
+func (loop *Loop) draw(current []*Scene) error {
+	if !loop.config.Headless {
+		render.Clear(render.ClearBitColor | render.ClearBitDepth)
+		drawn := map[*render.Layout]struct{}{}
+
+		for _, scene := range current {
+			err := scene.draw()
+
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, scene := range current {
+			if _, ok := drawn[scene.layout]; ok {
+				continue
+			}
+
+			err := scene.layout.Draw()
+
+			if err != nil {
+				return err
+			}
+
+			drawn[scene.layout] = struct{}{}
+		}
+	}
+
+	if loop.config.Draw != nil {
+		err := loop.config.Draw(loop.alpha)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if !loop.config.Headless {
+		system.TickLoop()
+		system.UpdateFrameRate()
+	}
+
+	return nil
+}
+
+// Run ticks the loop until it's stopped
+// or the window is closed.
+func (loop *Loop) Run() error {
+	defer loop.resetInput()
+
+	if !loop.config.Headless {
+		system.InitMetrics()
+	}
+
+	for !loop.stopped {
+		if !loop.config.Headless && system.ShouldClose() {
+			break
+		}
+
+		err := loop.Tick()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewLoop creates a new game loop
+// with the specified configuration.
+func NewLoop(config RunConfig) (*Loop, error) {
+	if config.MaxFixedSteps < 0 {
+		return nil, fmt.Errorf(
+			"the maximum number of fixed steps must not be negative, got %d",
+			config.MaxFixedSteps)
+	}
+
+	if config.TargetFPS < 0 {
+		return nil, fmt.Errorf(
+			"the target FPS must not be negative, got %d",
+			config.TargetFPS)
+	}
+
+	if config.MaxFixedSteps == 0 {
+		config.MaxFixedSteps = defaultMaxFixedSteps
+	}
+
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+
+	if config.InputRecorder != nil && config.InputPlayer != nil {
+		return nil, fmt.Errorf("the input cannot be recorded and played back at the same time")
+	}
+
+	var source interface {
+		system.InputSource
+		Seed() int64
+	}
+
+	switch {
+	case config.InputRecorder != nil:
+		source = config.InputRecorder
+
+	case config.InputPlayer != nil:
+		source = config.InputPlayer
+	}
+
+	if source != nil {
+		// The scenes may use the random
+		// source as soon as they're started.
+		if active := ActiveScene(); active != nil {
+			return nil, fmt.Errorf(
+				"scene '%s' is started before the loop seeds the random source",
+				active.name)
+		}
+
+		SetRandomSeed(source.Seed())
+		system.SetInputSource(source)
+	}
+
+	return &Loop{
+		config:         config,
+		clock:          config.Clock,
+		lastFrame:      config.Clock.Now(),
+		inputInstalled: source != nil,
+	}, nil
+}
+
+// Run creates a new game loop and runs
+// the current scenes until the loop is
+// stopped or the window is closed.
+func Run(config RunConfig) error {
+	loop, err := NewLoop(config)
+
+	if err != nil {
+		return err
+	}
+
+	return loop.Run()
+}
@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+// tickCounter is a test component which
+// counts its updates and fixed updates.
+type tickCounter struct {
+	BaseComponent
+	updates      int
+	fixedUpdates int
+}
+
+func (c *tickCounter) TypeID() string {
+	return "engine__TickCounter"
+}
+
+func (c *tickCounter) Update() error {
+	c.updates++
+	return nil
+}
+
+func (c *tickCounter) FixedUpdate() error {
+	c.fixedUpdates++
+	return nil
+}
+
+func newLoopTestScene(t *testing.T) (*tickCounter, func()) {
+	t.Helper()
+
+	scene := newTestScene(t, t.Name())
+	gmob := NewGameObject(nil, "ticker", nil)
+	comp := &tickCounter{}
+	comp.SetActive(true)
+
+	err := gmob.AddComponent(comp, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.AddGameObject(gmob, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.SetFixedTimestep(0.01)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = AddScene(scene)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return comp, func() {
+		RemoveScene(scene.name)
+		currentSceneName = ""
+	}
+}
+
+func TestLoopFixedSteps(t *testing.T) {
+	comp, cleanup := newLoopTestScene(t)
+	defer cleanup()
+
+	clock := NewManualClock(time.Unix(0, 0))
+	loop, err := NewLoop(RunConfig{
+		Headless:      true,
+		MaxFixedSteps: 3,
+		Clock:         clock,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		clock.Advance(25 * time.Millisecond)
+		err = loop.Tick()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if comp.updates != 10 {
+		t.Fatalf("wrong number of updates: %d", comp.updates)
+	}
+
+	if comp.fixedUpdates != 25 {
+		t.Fatalf("wrong number of fixed updates: %d", comp.fixedUpdates)
+	}
+
+	if alpha := loop.Alpha(); alpha < 0 || alpha >= 1 {
+		t.Fatalf("wrong alpha: %f", alpha)
+	}
+
+	// The loop can't catch up with a long frame.
+	clock.Advance(time.Second)
+	err = loop.Tick()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if comp.fixedUpdates != 28 {
+		t.Fatalf("the catch-up is not limited: %d", comp.fixedUpdates)
+	}
+}
+
+func TestLoopPauseAndStep(t *testing.T) {
+	comp, cleanup := newLoopTestScene(t)
+	defer cleanup()
+
+	clock := NewManualClock(time.Unix(0, 0))
+	loop, err := NewLoop(RunConfig{
+		Headless: true,
+		Clock:    clock,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loop.Pause()
+
+	for i := 0; i < 3; i++ {
+		clock.Advance(time.Second)
+		err = loop.Tick()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if comp.updates != 0 || comp.fixedUpdates != 0 {
+		t.Fatal("the paused loop updates the scene")
+	}
+
+	loop.StepFrame()
+	clock.Advance(time.Second)
+	err = loop.Tick()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if comp.updates != 1 || comp.fixedUpdates != 1 {
+		t.Fatalf("wrong step: %d updates, %d fixed updates",
+			comp.updates, comp.fixedUpdates)
+	}
+}
+
+func TestRunWithFPSLimit(t *testing.T) {
+	comp, cleanup := newLoopTestScene(t)
+	defer cleanup()
+
+	clock := NewManualClock(time.Unix(0, 0))
+	frames := 0
+	var loop *Loop
+
+	loop, err := NewLoop(RunConfig{
+		Headless:  true,
+		TargetFPS: 50,
+		Clock:     clock,
+		Draw: func(alpha float64) error {
+			frames++
+
+			if frames >= 4 {
+				loop.Stop()
+			}
+
+			return nil
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = loop.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each frame waits for 20 milliseconds.
+	if elapsed := clock.Now().Sub(time.Unix(0, 0)); elapsed != 80*time.Millisecond {
+		t.Fatalf("wrong elapsed time: %v", elapsed)
+	}
+
+	if comp.fixedUpdates != 8 {
+		t.Fatalf("wrong number of fixed updates: %d", comp.fixedUpdates)
+	}
+}
@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/zergon321/mempool"
+)
+
+// pooledGameObject is the game object
+// held by the object pool.
+type pooledGameObject struct {
+	gmob *GameObject
+}
+
+// Erase deactivates the game object and stops
+// drawing it so it stays on the scene idle.
+func (pooled *pooledGameObject) Erase() error {
+	if pooled.gmob == nil {
+		return nil
+	}
+
+	pooled.gmob.SetDraw(false)
+
+	return pooled.gmob.SetActive(false)
+}
+
+// resetGameObject resets all the resettable components
+// of the game object and its descendants.
+func resetGameObject(gmob *GameObject) error {
+	for _, entry := range gmob.componentOrder {
+		if comp, ok := entry.comp.(Resettable); ok {
+			err := comp.Reset()
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, child := range gmob.Children() {
+		err := resetGameObject(child)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ObjectPool holds the game objects instantiated
+// out of the prefab to reuse them instead of creating
+// and destroying them.
+//
+// The pooled game objects stay on the scene all the time.
+// An idle game object is inactive and not drawn, so
+// renting and returning it doesn't go through the
+// buffers of the scene.
+type ObjectPool struct {
+	scene                *Scene
+	prefab               *definitions.Prefab
+	instantiationOptions []InstantiationOption
+	objects              *mempool.Pool[*pooledGameObject]
+	rented               map[*GameObject]*pooledGameObject
+	idle                 int
+}
+
+// Scene returns the scene the game
+// objects of the pool are placed on.
+func (pool *ObjectPool) Scene() *Scene {
+	return pool.scene
+}
+
+// Rented returns the number of game objects
+// rented from the pool and not returned yet.
+func (pool *ObjectPool) Rented() int {
+	return len(pool.rented)
+}
+
+// Idle returns the number of game objects
+// waiting in the pool to be rented.
+func (pool *ObjectPool) Idle() int {
+	return pool.idle
+}
+
+// instantiate creates a new game object
+// out of the prefab and places it on the scene.
+// The game object is added to the scene on
+// the next update.
+func (pool *ObjectPool) instantiate() (*GameObject, error) {
+	return InstantiatePrefab(pool.scene, pool.prefab,
+		nil, pool.instantiationOptions...)
+}
+
+// Rent takes an idle game object from the pool or
+// instantiates a new one if the pool is empty.
+// All the resettable components of the game object
+// and its descendants are reset, and then the game
+// object is activated and set to be drawn.
+//
+// The transform of the game object is not reset.
+func (pool *ObjectPool) Rent() (*GameObject, error) {
+	var pooled *pooledGameObject
+
+	// Idle game objects could be destroyed
+	// by the scene, e.g. on scene switch.
+	for pool.idle > 0 {
+		pooled = pool.objects.Get()
+		pool.idle--
+
+		if !pooled.gmob.destroyed {
+			break
+		}
+
+		pooled = nil
+	}
+
+	if pooled == nil {
+		gmob, err := pool.instantiate()
+
+		if err != nil {
+			return nil, err
+		}
+
+		pooled = &pooledGameObject{gmob: gmob}
+	}
+
+	err := resetGameObject(pooled.gmob)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = pooled.gmob.SetActive(true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pooled.gmob.SetDraw(true)
+	pool.rented[pooled.gmob] = pooled
+
+	return pooled.gmob, nil
+}
+
+// Return puts the rented game object back
+// to the pool. The game object is deactivated
+// and stops being drawn but it's not destroyed.
+func (pool *ObjectPool) Return(gmob *GameObject) error {
+	pooled, ok := pool.rented[gmob]
+
+	if !ok {
+		return fmt.Errorf("game object '%s' is not rented from the pool",
+			gmob.name)
+	}
+
+	delete(pool.rented, gmob)
+
+	if gmob.destroyed {
+		return fmt.Errorf("game object '%s' is destroyed", gmob.name)
+	}
+
+	err := pool.objects.Put(pooled)
+
+	if err != nil {
+		return err
+	}
+
+	pool.idle++
+
+	return nil
+}
+
+// NewObjectPool creates a new pool of the game
+// objects instantiated out of the prefab on the scene.
+// The game objects get unique generated names with
+// the names from the prefab as prefixes.
+func NewObjectPool(scene *Scene, prefab *definitions.Prefab, options ...ObjectPoolOption) (*ObjectPool, error) {
+	if scene == nil {
+		return nil, fmt.Errorf("the scene is nil")
+	}
+
+	if prefab == nil {
+		return nil, fmt.Errorf("the prefab is nil")
+	}
+
+	var params objectPoolParameters
+
+	for i := 0; i < len(options); i++ {
+		option := options[i]
+		err := option(&params)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objects, err := mempool.NewPool[*pooledGameObject](
+		func() *pooledGameObject {
+			return &pooledGameObject{}
+		})
+
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &ObjectPool{
+		scene:  scene,
+		prefab: prefab,
+		instantiationOptions: append(params.instantiationOptions,
+			InstantiationOptionWithAutoNames()),
+		objects: objects,
+		rented:  map[*GameObject]*pooledGameObject{},
+	}
+
+	for i := 0; i < params.prewarm; i++ {
+		gmob, err := pool.instantiate()
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = pool.objects.Put(&pooledGameObject{gmob: gmob})
+
+		if err != nil {
+			return nil, err
+		}
+
+		pool.idle++
+	}
+
+	return pool, nil
+}
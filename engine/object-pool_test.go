@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/alacrity-engine/core/definitions"
+)
+
+// Reset makes the counter resettable
+// for the object pool tests.
+func (c *counter) Reset() error {
+	c.Value = 0
+	return nil
+}
+
+func TestObjectPoolReusesGameObjects(t *testing.T) {
+	scene := newTestScene(t, "pool")
+	prefab := &definitions.Prefab{
+		Name: "bullet",
+		TransformRoot: &definitions.TransformDefinition{
+			Gmob: counterDefinition("bullet", 10, ""),
+			Children: []*definitions.TransformDefinition{{
+				Gmob: counterDefinition("trail", 10, ""),
+			}},
+		},
+	}
+	prefab.TransformRoot.Children[0].Gmob.Draw = true
+
+	pool, err := NewObjectPool(scene, prefab,
+		ObjectPoolOptionWithPrewarm(2))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pool.Idle() != 2 {
+		t.Fatalf("wrong number of idle game objects: %d", pool.Idle())
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The idle game objects are on the scene
+	// but they're neither updated nor drawn.
+	gmobs := scene.Query().WithComponents(testCounterTypeID).GameObjects()
+
+	if len(gmobs) != 4 {
+		t.Fatalf("wrong number of pooled game objects: %d", len(gmobs))
+	}
+
+	for _, gmob := range gmobs {
+		if gmob.ActiveInHierarchy() || gmob.FindComponent(testCounterTypeID).(*counter).Value != 10 {
+			t.Fatalf("idle game object '%s' is updated", gmob.Name())
+		}
+
+		if gmob.ShouldBeDrawn() {
+			t.Fatalf("idle game object '%s' is drawn", gmob.Name())
+		}
+	}
+
+	bullet, err := pool.Rent()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comp := bullet.FindComponent(testCounterTypeID).(*counter)
+
+	if !bullet.Active() || !bullet.ShouldBeDrawn() || comp.Value != 0 {
+		t.Fatal("the rented game object is not activated or reset")
+	}
+
+	// The descendants are reset as well.
+	trail := bullet.Children()[0]
+
+	if !trail.ShouldBeDrawn() || trail.FindComponent(testCounterTypeID).(*counter).Value != 0 {
+		t.Fatal("the descendant of the rented game object is not reset")
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if comp.Value != 1 {
+		t.Fatalf("the rented game object is not updated: %d", comp.Value)
+	}
+
+	err = pool.Return(bullet)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bullet.Active() || bullet.ShouldBeDrawn() || bullet.Destroyed() {
+		t.Fatal("the returned game object is not deactivated")
+	}
+
+	err = pool.Return(bullet)
+
+	if err == nil {
+		t.Fatal("no error for the game object returned twice")
+	}
+
+	// Renting more game objects than the pool
+	// holds instantiates the new ones.
+	rented := map[*GameObject]struct{}{}
+
+	for i := 0; i < 3; i++ {
+		gmob, err := pool.Rent()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rented[gmob] = struct{}{}
+	}
+
+	if len(rented) != 3 || pool.Rented() != 3 || pool.Idle() != 0 {
+		t.Fatalf("wrong numbers of game objects: %d rented, %d idle",
+			pool.Rented(), pool.Idle())
+	}
+
+	if _, ok := rented[bullet]; !ok {
+		t.Fatal("the returned game object is not reused")
+	}
+}
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"io"
+
+	"github.com/alacrity-engine/core/anim"
+	"github.com/alacrity-engine/core/render"
+	"github.com/alacrity-engine/core/resources"
+	"github.com/golang/freetype/truetype"
+)
+
+// ownedResource is a resource loaded by
+// the engine for the game object along
+// with the loader it was loaded by.
+type ownedResource struct {
+	loader   *resources.ResourceLoader
+	resource interface{}
+}
+
+// ownResource makes the game object hold the
+// reference to the resource acquired by the load,
+// so the resource is released when the game object
+// is destroyed. The resources which are not
+// reference counted are not held.
+func (gmob *GameObject) ownResource(loader *resources.ResourceLoader, resource interface{}) {
+	switch resource.(type) {
+	case *render.Texture, *render.Picture, *truetype.Font,
+		*anim.Animation, *resources.Spritesheet, io.ReadCloser:
+		gmob.resources = append(gmob.resources, ownedResource{
+			loader:   loader,
+			resource: resource,
+		})
+	}
+}
+
+// releaseResources releases all the resources
+// loaded by the engine for the game object. The
+// audio streams are closed. All the resources are
+// released even if some of them fail, and the
+// first error is returned.
+func (gmob *GameObject) releaseResources() error {
+	var releaseErr error
+
+	for _, owned := range gmob.resources {
+		var err error
+
+		if stream, ok := owned.resource.(io.ReadCloser); ok {
+			err = stream.Close()
+		} else {
+			err = owned.loader.Release(owned.resource)
+		}
+
+		if err != nil && releaseErr == nil {
+			releaseErr = err
+		}
+	}
+
+	gmob.resources = nil
+
+	return releaseErr
+}
+
+// detachSprite removes the sprite
+// from its batch or its canvas.
+func detachSprite(sprite *render.Sprite) {
+	if batch := sprite.Batch(); batch != nil {
+		batch.DetachSprite(sprite)
+	} else if canvas := sprite.Canvas(); canvas != nil {
+		canvas.RemoveSprite(sprite)
+	}
+}
@@ -0,0 +1,162 @@
+package engine
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/resources"
+	"github.com/golang/freetype/truetype"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// label is a test component
+// which holds the font.
+type label struct {
+	BaseComponent
+	Font   *truetype.Font
+	Target *GameObject
+}
+
+func (l *label) TypeID() string {
+	return "engine__Label"
+}
+
+func newTestFontLoader(t *testing.T) *resources.ResourceLoader {
+	t.Helper()
+
+	fname := filepath.Join(t.TempDir(), "resources.db")
+	db, err := bolt.Open(fname, 0666, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		buck, err := tx.CreateBucketIfNotExists([]byte("fonts"))
+
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte("regular"), goregular.TTF)
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Close()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loader, err := resources.NewResourceLoader(fname)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		loader.Close()
+	})
+
+	return loader
+}
+
+func labelPrefab(target string) *definitions.Prefab {
+	data := map[string]interface{}{
+		"Font": definitions.ResourcePointer{
+			ResourceType: definitions.ResourceTypeFont,
+			ResourceID:   "regular",
+		},
+	}
+
+	if target != "" {
+		data["Target"] = definitions.GameObjectPointer{Name: target}
+	}
+
+	return &definitions.Prefab{
+		Name: "label",
+		TransformRoot: &definitions.TransformDefinition{
+			Gmob: &definitions.GameObjectDefinition{
+				Name:   "label",
+				Active: true,
+				Components: []*definitions.ComponentDefinition{{
+					TypeName: "engine__Label",
+					Active:   true,
+					Data:     data,
+				}},
+			},
+		},
+	}
+}
+
+func TestGameObjectReleasesLoadedResources(t *testing.T) {
+	compTypeRegistry["engine__Label"] = ComponentTypeEntry{
+		Name:        "Label",
+		PkgPath:     "github.com/alacrity-engine/core/engine",
+		Constructor: func() Component { return &label{} },
+		Fields: map[string]ComponentTypeFieldEntry{
+			"Font": {
+				Name:   "Font",
+				Type:   "*truetype.Font",
+				Setter: func(comp Component, value interface{}) { comp.(*label).Font = value.(*truetype.Font) },
+			},
+			"Target": {
+				Name:   "Target",
+				Type:   "*engine.GameObject",
+				Setter: func(comp Component, value interface{}) { comp.(*label).Target = value.(*GameObject) },
+			},
+		},
+	}
+
+	t.Cleanup(func() {
+		delete(compTypeRegistry, "engine__Label")
+	})
+
+	scene := newTestScene(t, "resources")
+	loader := newTestFontLoader(t)
+
+	// The failed instantiation
+	// releases the loaded font.
+	_, err := InstantiatePrefab(scene, labelPrefab("missing"), nil,
+		InstantiationOptionWithResourceLoader(loader))
+
+	if err == nil {
+		t.Fatal("no error for the unresolved pointer")
+	}
+
+	if refs := loader.References(definitions.ResourceTypeFont, "regular"); refs != 0 {
+		t.Fatalf("the font of the failed instantiation is referenced: %d references", refs)
+	}
+
+	gmob, err := InstantiatePrefab(scene, labelPrefab(""), nil,
+		InstantiationOptionWithResourceLoader(loader))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if refs := loader.References(definitions.ResourceTypeFont, "regular"); refs != 1 {
+		t.Fatalf("wrong number of references: %d", refs)
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.DestroyGameObject(gmob.Name())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if refs := loader.References(definitions.ResourceTypeFont, "regular"); refs != 0 {
+		t.Fatalf("the font of the destroyed game object is referenced: %d references", refs)
+	}
+}
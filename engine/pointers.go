@@ -0,0 +1,240 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/alacrity-engine/core/definitions"
+)
+
+// pendingPointer is a pointer from the component
+// data that should be resolved and assigned
+// to the component field after all the game
+// objects are built.
+type pendingPointer struct {
+	gmob      *GameObject
+	gmobName  string
+	typeName  string
+	comp      Component
+	field     ComponentTypeFieldEntry
+	fieldName string
+	pointer   interface{}
+}
+
+// isPointer returns true if the value is
+// one of the pointer types from the definitions.
+func isPointer(value interface{}) bool {
+	switch value.(type) {
+	case definitions.GameObjectPointer, *definitions.GameObjectPointer,
+		definitions.ComponentPointer, *definitions.ComponentPointer,
+		definitions.ResourcePointer, *definitions.ResourcePointer,
+		definitions.BatchPointer, *definitions.BatchPointer:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// resolvePointers resolves all the postponed pointers
+// and assigns the obtained values to the component fields.
+func (builder *prefabBuilder) resolvePointers() error {
+	for _, pending := range builder.pointers {
+		value, err := builder.resolvePointer(pending.gmob, pending.pointer)
+
+		if err != nil {
+			return NewErrorUnresolvedPointer(pending.gmobName,
+				pending.typeName, pending.fieldName, pending.pointer, err)
+		}
+
+		pending.field.Setter(pending.comp, value)
+	}
+
+	builder.pointers = []pendingPointer{}
+
+	return nil
+}
+
+// resolvePointer returns the object the pointer refers
+// to. The resources are loaded for the game object.
+func (builder *prefabBuilder) resolvePointer(gmob *GameObject, pointer interface{}) (interface{}, error) {
+	switch ptr := pointer.(type) {
+	case definitions.GameObjectPointer:
+		return builder.resolveGameObjectPointer(ptr)
+
+	case *definitions.GameObjectPointer:
+		return builder.resolveGameObjectPointer(*ptr)
+
+	case definitions.ComponentPointer:
+		return builder.resolveComponentPointer(ptr)
+
+	case *definitions.ComponentPointer:
+		return builder.resolveComponentPointer(*ptr)
+
+	case definitions.ResourcePointer:
+		return builder.resolveResourcePointer(gmob, ptr)
+
+	case *definitions.ResourcePointer:
+		return builder.resolveResourcePointer(gmob, *ptr)
+
+	case definitions.BatchPointer:
+		return builder.resolveBatchPointer(ptr)
+
+	case *definitions.BatchPointer:
+		return builder.resolveBatchPointer(*ptr)
+
+	default:
+		return nil, fmt.Errorf("unknown pointer type '%T'", pointer)
+	}
+}
+
+// findGameObject searches for the game object
+// among the built ones and then on the scene.
+func (builder *prefabBuilder) findGameObject(name string) *GameObject {
+	if gmob, ok := builder.nameIndex[name]; ok {
+		return gmob
+	}
+
+	if gmob := builder.scene.FindGameObject(name); gmob != nil {
+		return gmob
+	}
+
+	for _, buffered := range builder.scene.addBuffer {
+		if buffered.gmob.name == name {
+			return buffered.gmob
+		}
+	}
+
+	return nil
+}
+
+// resolveGameObjectPointer returns the game object
+// the pointer refers to.
+func (builder *prefabBuilder) resolveGameObjectPointer(ptr definitions.GameObjectPointer) (*GameObject, error) {
+	if ptr.Name == "" && ptr.Handle() != 0 {
+		return builder.findGameObjectByHandle(HandleFromUint64(ptr.Handle()))
+	}
+
+	gmob := builder.findGameObject(ptr.Name)
+
+	if gmob == nil {
+		return nil, RaiseErrorNoGameObjectOnScene(builder.scene, ptr.Name)
+	}
+
+	return gmob, nil
+}
+
+// findGameObjectByHandle searches for the game object
+// referred to by the handle among the built ones
+// and then on the scene.
+func (builder *prefabBuilder) findGameObjectByHandle(handle Handle) (*GameObject, error) {
+	gmob := handle.GameObject()
+
+	if gmob == nil {
+		return nil, NewErrorStaleHandle(handle)
+	}
+
+	for _, built := range builder.gmobs {
+		if built.gmob == gmob {
+			return gmob, nil
+		}
+	}
+
+	if builder.scene.FindGameObjectByHandle(handle) != nil {
+		return gmob, nil
+	}
+
+	if found, _ := builder.scene.findGameObjectInAdded(gmob); found {
+		return gmob, nil
+	}
+
+	return nil, RaiseErrorNoGameObjectOnScene(builder.scene, gmob.name)
+}
+
+// resolveComponentPointer returns the component
+// the pointer refers to.
+func (builder *prefabBuilder) resolveComponentPointer(ptr definitions.ComponentPointer) (Component, error) {
+	gmob := builder.findGameObject(ptr.GmobName)
+
+	if gmob == nil {
+		return nil, RaiseErrorNoGameObjectOnScene(builder.scene, ptr.GmobName)
+	}
+
+	components := gmob.FindComponents(ptr.CompType)
+
+	if len(components) <= 0 {
+		return nil, RaiseErrorNoComponentOnGameObject(gmob, ptr.CompType)
+	}
+
+	if ptr.Index < 0 || ptr.Index >= len(components) {
+		return nil, fmt.Errorf(
+			"game object '%s' has no component '%s' at index %d",
+			gmob.name, ptr.CompType, ptr.Index)
+	}
+
+	return components[ptr.Index], nil
+}
+
+// resolveResourcePointer loads the resource the
+// pointer refers to. The game object holds the
+// reference to the loaded resource.
+func (builder *prefabBuilder) resolveResourcePointer(gmob *GameObject, ptr definitions.ResourcePointer) (interface{}, error) {
+	loader := builder.params.resourceLoader
+
+	if loader == nil {
+		return nil, fmt.Errorf("no resource loader to load %s '%s'",
+			ptr.ResourceType, ptr.ResourceID)
+	}
+
+	var resource interface{}
+	var err error
+
+	switch ptr.ResourceType {
+	case definitions.ResourceTypeAnimation:
+		resource, err = loader.LoadAnimation(ptr.ResourceID)
+
+	case definitions.ResourceTypeAudio:
+		resource, err = loader.LoadAudio(ptr.ResourceID)
+
+	case definitions.ResourceTypePicture:
+		resource, err = loader.LoadPicture(ptr.ResourceID)
+
+	case definitions.ResourceTypeTexture:
+		resource, err = loader.LoadTexture(ptr.ResourceID)
+
+	case definitions.ResourceTypeFont:
+		resource, err = loader.LoadFont(ptr.ResourceID)
+
+	case definitions.ResourceTypeSpritesheet:
+		resource, err = loader.LoadSpritesheet(ptr.ResourceID)
+
+	case definitions.ResourceTypeShader:
+		resource, err = loader.LoadShader(ptr.ResourceID)
+
+	case definitions.ResourceTypeShaderProgram:
+		resource, err = loader.LoadShaderProgram(ptr.ResourceID)
+
+	default:
+		return nil, fmt.Errorf("resources of type '%s' cannot be loaded",
+			ptr.ResourceType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	gmob.ownResource(loader, resource)
+
+	return resource, nil
+}
+
+// resolveBatchPointer returns the batch
+// the pointer refers to.
+func (builder *prefabBuilder) resolveBatchPointer(ptr definitions.BatchPointer) (interface{}, error) {
+	canvas, err := builder.scene.layout.CanvasByName(ptr.CanvasID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return canvas.BatchByName(ptr.BatchID)
+}
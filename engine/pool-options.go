@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"fmt"
+)
+
+// ObjectPoolOption changes the way
+// the object pool is created.
+type ObjectPoolOption func(params *objectPoolParameters) error
+
+// objectPoolParameters holds the parameters
+// to be used for creation of the object pool.
+type objectPoolParameters struct {
+	prewarm              int
+	instantiationOptions []InstantiationOption
+}
+
+// ObjectPoolOptionWithPrewarm sets the number of
+// game objects to be instantiated when the pool
+// is created.
+func ObjectPoolOptionWithPrewarm(count int) ObjectPoolOption {
+	return func(params *objectPoolParameters) error {
+		if count < 0 {
+			return fmt.Errorf(
+				"wrong prewarm count: '%d'", count)
+		}
+
+		params.prewarm = count
+		return nil
+	}
+}
+
+// ObjectPoolOptionWithInstantiationOptions sets
+// the options to instantiate the prefab with.
+func ObjectPoolOptionWithInstantiationOptions(options ...InstantiationOption) ObjectPoolOption {
+	return func(params *objectPoolParameters) error {
+		for _, option := range options {
+			if option == nil {
+				return fmt.Errorf("the instantiation option is nil")
+			}
+		}
+
+		params.instantiationOptions = append(
+			params.instantiationOptions, options...)
+		return nil
+	}
+}
@@ -0,0 +1,533 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/math/geometry"
+	"github.com/alacrity-engine/core/render"
+)
+
+type (
+	// prefabBuilder creates game objects
+	// out of the transform definitions.
+	prefabBuilder struct {
+		scene     *Scene
+		params    instantiationParameters
+		gmobs     []builtGameObject
+		nameIndex map[string]*GameObject
+		pointers  []pendingPointer
+		sprites   []*render.Sprite
+	}
+
+	// builtGameObject is a game object created
+	// out of the definition and waiting to be
+	// added to the scene.
+	builtGameObject struct {
+		gmob *GameObject
+		zUpd float32
+	}
+)
+
+// buildTransform creates the transform and the game object
+// out of the definition and then recursively builds
+// all the child transforms.
+func (builder *prefabBuilder) buildTransform(def *definitions.TransformDefinition, parent *geometry.Transform) (*geometry.Transform, error) {
+	var transform *geometry.Transform
+
+	if def.Gmob != nil {
+		gmob, err := builder.buildGameObject(def.Gmob)
+
+		if err != nil {
+			return nil, err
+		}
+
+		transform = gmob.Transform()
+	} else {
+		transform = geometry.NewTransform(nil)
+	}
+
+	if parent != nil {
+		err := transform.SetParent(parent)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The transform has no children yet
+	// so the values of the definition
+	// are applied to it only.
+	transform.MoveTo(def.Position)
+
+	if def.Angle != 0 {
+		transform.Rotate(def.Angle)
+	}
+
+	if def.Scale != geometry.ZV {
+		transform.ApplyScale(def.Scale)
+	}
+
+	for _, childDef := range def.Children {
+		if childDef == nil {
+			continue
+		}
+
+		_, err := builder.buildTransform(childDef, transform)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return transform, nil
+}
+
+// buildGameObject creates the game object
+// with all its components and the sprite.
+//
+// The names from the definitions must be unique
+// as the pointers refer to the game objects by them.
+func (builder *prefabBuilder) buildGameObject(def *definitions.GameObjectDefinition) (*GameObject, error) {
+	if _, ok := builder.nameIndex[def.Name]; ok {
+		return nil, fmt.Errorf(
+			"more than one game object is defined under the name '%s'", def.Name)
+	}
+
+	name := def.Name
+
+	if builder.params.autoNames {
+		name = GenerateGameObjectName(def.Name)
+	}
+
+	gmob := NewGameObject(nil, name, nil)
+
+	for _, compDef := range def.Components {
+		if compDef == nil {
+			continue
+		}
+
+		comp, err := builder.buildComponent(gmob, compDef)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = gmob.AddComponent(comp, compDef.Priority)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if def.Sprite != nil {
+		sprite, err := builder.buildSprite(gmob, def.Sprite)
+
+		if err != nil {
+			gmob.releaseResources()
+			return nil, err
+		}
+
+		gmob.SetSprite(sprite)
+	}
+
+	// The game object is not started yet,
+	// so its components aren't notified.
+	err := gmob.SetActive(def.Active)
+
+	if err != nil {
+		gmob.releaseResources()
+		return nil, err
+	}
+
+	gmob.SetDraw(def.Draw)
+	gmob.SetLayer(LayerMask(def.Layer))
+
+	for _, tag := range def.Tags {
+		gmob.AddTag(tag)
+	}
+
+	builder.nameIndex[def.Name] = gmob
+	builder.gmobs = append(builder.gmobs, builtGameObject{
+		gmob: gmob,
+		zUpd: float32(def.ZUpdate),
+	})
+
+	return gmob, nil
+}
+
+// buildComponent creates a new component with the
+// registered constructor and assigns values to its fields.
+//
+// Pointer values are not assigned immediately but
+// postponed until all the game objects are built.
+func (builder *prefabBuilder) buildComponent(gmob *GameObject, def *definitions.ComponentDefinition) (Component, error) {
+	entry, ok := compTypeRegistry[def.TypeName]
+
+	if !ok || entry.Constructor == nil {
+		return nil, NewErrorComponentTypeNotRegistered(def.TypeName)
+	}
+
+	comp := entry.Constructor()
+
+	for fieldName, value := range def.Data {
+		field, ok := entry.Fields[fieldName]
+
+		if !ok || field.Setter == nil {
+			return nil, NewErrorNoComponentTypeField(def.TypeName, fieldName)
+		}
+
+		if isPointer(value) {
+			builder.pointers = append(builder.pointers, pendingPointer{
+				gmob:      gmob,
+				gmobName:  gmob.name,
+				typeName:  def.TypeName,
+				comp:      comp,
+				field:     field,
+				fieldName: fieldName,
+				pointer:   value,
+			})
+
+			continue
+		}
+
+		field.Setter(comp, value)
+	}
+
+	comp.SetActive(def.Active)
+
+	return comp, nil
+}
+
+// buildSprite creates a new sprite for the game
+// object and places it onto the scene canvas.
+// The game object holds the texture of the sprite.
+func (builder *prefabBuilder) buildSprite(gmob *GameObject, def *definitions.SpriteDefinition) (*render.Sprite, error) {
+	if builder.params.resourceLoader == nil {
+		return nil, fmt.Errorf(
+			"no resource loader to load texture '%s'", def.TextureID)
+	}
+
+	// The shader program of the sprite definition
+	// overrides the one of the instantiation.
+	program := builder.params.shaderProgram
+
+	if def.ShaderProgramID != "" {
+		var err error
+		program, err = builder.params.resourceLoader.
+			LoadShaderProgram(def.ShaderProgramID)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if program == nil {
+		return nil, fmt.Errorf(
+			"no shader program for the sprite with texture '%s'", def.TextureID)
+	}
+
+	texture, targetArea, err := builder.spriteTexture(gmob, def)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sprite, err := render.NewSpriteFromTextureAndProgram(
+		drawModeOrDefault(def.VertexDrawMode),
+		drawModeOrDefault(def.TextureDrawMode),
+		drawModeOrDefault(def.ColorDrawMode),
+		texture, program, targetArea)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if def.ColorMask != (render.ColorMask{}) {
+		err = sprite.SetColorMask(def.ColorMask)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	canvas, err := builder.scene.layout.CanvasByName(def.CanvasID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if def.BatchID != "" {
+		batch, err := canvas.BatchByName(def.BatchID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = canvas.AttachSpriteToBatch(batch, sprite)
+
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err = canvas.AddSprite(sprite)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	builder.sprites = append(builder.sprites, sprite)
+
+	return sprite, nil
+}
+
+// spriteTexture loads the texture of the sprite and
+// returns the area of the texture the sprite draws.
+// If the sprite refers to a frame of a spritesheet,
+// the frame is drawn instead of the target area.
+// The game object holds the loaded texture or
+// spritesheet.
+func (builder *prefabBuilder) spriteTexture(gmob *GameObject, def *definitions.SpriteDefinition) (*render.Texture, geometry.Rect, error) {
+	loader := builder.params.resourceLoader
+
+	if def.SpritesheetID == "" {
+		texture, err := loader.LoadTexture(def.TextureID)
+
+		if err != nil {
+			return nil, geometry.Rect{}, err
+		}
+
+		gmob.ownResource(loader, texture)
+
+		return texture, def.TargetArea, nil
+	}
+
+	ss, err := loader.LoadSpritesheet(def.SpritesheetID)
+
+	if err != nil {
+		return nil, geometry.Rect{}, err
+	}
+
+	gmob.ownResource(loader, ss)
+
+	if def.FrameName == "" {
+		return ss.Texture(), def.TargetArea, nil
+	}
+
+	area, err := ss.FrameByName(def.FrameName)
+
+	if err != nil {
+		return nil, geometry.Rect{}, err
+	}
+
+	return ss.Texture(), area, nil
+}
+
+// addToScene sets all the built game
+// objects to be added to the scene.
+func (builder *prefabBuilder) addToScene() error {
+	// The names are checked beforehand for none
+	// of the game objects to be left in the buffer
+	// if one of them can't be added.
+	err := builder.ensureNamesFree()
+
+	if err != nil {
+		return err
+	}
+
+	for _, built := range builder.gmobs {
+		err := builder.scene.AddGameObjectInRuntime(
+			built.gmob, built.zUpd)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureNamesFree returns an error if any of the built
+// game objects can't be added to the scene because
+// the scene already has a game object with its name.
+func (builder *prefabBuilder) ensureNamesFree() error {
+	for _, built := range builder.gmobs {
+		name := built.gmob.name
+
+		if builder.scene.HasGameObject(name) {
+			return fmt.Errorf("scene '%s' already has game object '%s'",
+				builder.scene.name, name)
+		}
+
+		for _, buffered := range builder.scene.addBuffer {
+			if buffered.gmob.name == name {
+				return fmt.Errorf("game object '%s' is already set to be added",
+					name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// discard removes the sprites placed by the builder
+// from their canvases and batches, releases the
+// resources loaded for the built game objects and
+// detaches the built transforms from their parents,
+// so nothing is left behind when the instantiation
+// fails.
+//
+// The cleanup is best effort: the sprites which
+// can't be removed and the resources which can't
+// be released are skipped.
+func (builder *prefabBuilder) discard() {
+	for _, sprite := range builder.sprites {
+		detachSprite(sprite)
+	}
+
+	for _, built := range builder.gmobs {
+		built.gmob.releaseResources()
+		built.gmob.transform.SetParent(nil)
+	}
+
+	builder.sprites = []*render.Sprite{}
+}
+
+// drawModeOrDefault returns the static draw
+// mode if the draw mode is not specified.
+func drawModeOrDefault(mode render.DrawMode) render.DrawMode {
+	if mode == 0 {
+		return render.DrawModeStatic
+	}
+
+	return mode
+}
+
+// newPrefabBuilder creates a new builder to
+// instantiate definitions on the scene.
+func newPrefabBuilder(scene *Scene, options ...InstantiationOption) (*prefabBuilder, error) {
+	if scene == nil {
+		return nil, fmt.Errorf("the scene is nil")
+	}
+
+	var params instantiationParameters
+
+	for i := 0; i < len(options); i++ {
+		option := options[i]
+		err := option(&params)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &prefabBuilder{
+		scene:     scene,
+		params:    params,
+		gmobs:     []builtGameObject{},
+		nameIndex: map[string]*GameObject{},
+		pointers:  []pendingPointer{},
+		sprites:   []*render.Sprite{},
+	}, nil
+}
+
+// InstantiatePrefab creates game objects out of the prefab,
+// attaches the root transform of the prefab to the parent
+// and sets all the created game objects to be added to the
+// scene on the next update.
+//
+// Positions, angles and scales of the prefab transforms
+// are applied as they are, i.e. in the world space.
+// Pointers stored in the component data are resolved
+// after all the game objects of the prefab are created,
+// so they may reference each other in any order.
+// The root transform must hold a game object which
+// is returned as a result.
+func InstantiatePrefab(scene *Scene, prefab *definitions.Prefab, parent *geometry.Transform, options ...InstantiationOption) (*GameObject, error) {
+	if prefab == nil || prefab.TransformRoot == nil {
+		return nil, fmt.Errorf("the prefab has no root transform")
+	}
+
+	if prefab.TransformRoot.Gmob == nil {
+		return nil, fmt.Errorf(
+			"the root transform of prefab '%s' has no game object", prefab.Name)
+	}
+
+	builder, err := newPrefabBuilder(scene, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = builder.buildTransform(prefab.TransformRoot, parent)
+
+	if err != nil {
+		builder.discard()
+		return nil, err
+	}
+
+	err = builder.resolvePointers()
+
+	if err != nil {
+		builder.discard()
+		return nil, err
+	}
+
+	err = builder.addToScene()
+
+	if err != nil {
+		builder.discard()
+		return nil, err
+	}
+
+	return builder.gmobs[0].gmob, nil
+}
+
+// InstantiateScene creates game objects out of the scene
+// definition and sets all of them to be added to the scene
+// on the next update.
+//
+// The resource loader used to load the resources
+// for the snapshot should be passed in the options
+// for resource pointers to be resolved.
+func InstantiateScene(scene *Scene, def *definitions.SceneDefinition, options ...InstantiationOption) error {
+	if def == nil {
+		return fmt.Errorf("the scene definition is nil")
+	}
+
+	builder, err := newPrefabBuilder(scene, options...)
+
+	if err != nil {
+		return err
+	}
+
+	for _, transformDef := range def.Transforms {
+		if transformDef == nil {
+			continue
+		}
+
+		_, err = builder.buildTransform(transformDef, nil)
+
+		if err != nil {
+			builder.discard()
+			return err
+		}
+	}
+
+	err = builder.resolvePointers()
+
+	if err != nil {
+		builder.discard()
+		return err
+	}
+
+	err = builder.addToScene()
+
+	if err != nil {
+		builder.discard()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/render"
+)
+
+func spritePrefab(programID string) *definitions.Prefab {
+	return &definitions.Prefab{
+		Name: "player",
+		TransformRoot: &definitions.TransformDefinition{
+			Gmob: &definitions.GameObjectDefinition{
+				Name:   "player",
+				Active: true,
+				Sprite: &definitions.SpriteDefinition{
+					ShaderProgramID: programID,
+					TextureID:       "player",
+				},
+			},
+		},
+	}
+}
+
+func TestSpriteShaderProgramOverride(t *testing.T) {
+	scene := newTestScene(t, "sprites")
+	loader := newTestFontLoader(t)
+
+	_, err := InstantiatePrefab(scene, spritePrefab(""), nil,
+		InstantiationOptionWithResourceLoader(loader))
+
+	if err == nil || !strings.Contains(err.Error(), "no shader program") {
+		t.Fatalf("wrong error for the sprite without shader program: %v", err)
+	}
+
+	// The shader program of the sprite definition
+	// is loaded even if the instantiation has one.
+	_, err = InstantiatePrefab(scene, spritePrefab("outline"), nil,
+		InstantiationOptionWithResourceLoader(loader),
+		InstantiationOptionWithShaderProgram(&render.ShaderProgram{}))
+
+	if err == nil || !strings.Contains(err.Error(), "shader-programs") {
+		t.Fatalf("the shader program of the sprite is not loaded: %v", err)
+	}
+
+	if scene.FindGameObject("player") != nil {
+		t.Fatal("the failed game object is on the scene")
+	}
+}
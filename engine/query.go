@@ -0,0 +1,302 @@
+package engine
+
+import "sort"
+
+// Query selects game objects of the scene by their
+// components, tags, layers and arbitrary conditions. Only the game
+// objects placed on the scene and not destroyed are
+// selected, the ones set to be added are not.
+type Query struct {
+	scene      *Scene
+	typeIDs    []string
+	tags       []string
+	layer      LayerMask
+	layerSet   bool
+	predicates []func(gmob *GameObject) bool
+}
+
+// WithComponents makes the query select only the game
+// objects having components of all the specified types.
+func (query *Query) WithComponents(typeIDs ...string) *Query {
+	query.typeIDs = append(query.typeIDs, typeIDs...)
+
+	return query
+}
+
+// WithTags makes the query select only the game
+// objects having all the specified tags.
+func (query *Query) WithTags(tags ...string) *Query {
+	query.tags = append(query.tags, tags...)
+
+	return query
+}
+
+// WithLayer makes the query select only the game
+// objects belonging to any layer of the mask.
+// LayerNone selects no game objects.
+func (query *Query) WithLayer(layer LayerMask) *Query {
+	query.layer = layer
+	query.layerSet = true
+
+	return query
+}
+
+// Where makes the query select only the game
+// objects satisfying the predicate.
+func (query *Query) Where(predicate func(gmob *GameObject) bool) *Query {
+	query.predicates = append(query.predicates, predicate)
+
+	return query
+}
+
+// GameObjects returns all the game objects
+// selected by the query in the Z update order.
+func (query *Query) GameObjects() []*GameObject {
+	if len(query.typeIDs) <= 0 && len(query.tags) <= 0 {
+		return query.scanGameObjects()
+	}
+
+	// Start with the smallest index
+	// to check the fewest game objects.
+	var candidates map[*GameObject]struct{}
+	indices := make([]map[*GameObject]struct{}, 0,
+		len(query.typeIDs)+len(query.tags))
+
+	for _, typeID := range query.typeIDs {
+		indices = append(indices, query.scene.componentIndex[typeID])
+	}
+
+	for _, tag := range query.tags {
+		indices = append(indices, query.scene.tagIndex[tag])
+	}
+
+	for i, index := range indices {
+		if i == 0 || len(index) < len(candidates) {
+			candidates = index
+		}
+	}
+
+	gmobs := make([]*GameObject, 0, len(candidates))
+
+	for gmob := range candidates {
+		if query.matches(gmob) {
+			gmobs = append(gmobs, gmob)
+		}
+	}
+
+	sort.Slice(gmobs, func(i, j int) bool {
+		return gmobs[i].zUpdateKey().Less(gmobs[j].zUpdateKey())
+	})
+
+	return gmobs
+}
+
+// First returns the first game object selected
+// by the query in the Z update order.
+func (query *Query) First() *GameObject {
+	gmobs := query.GameObjects()
+
+	if len(gmobs) <= 0 {
+		return nil
+	}
+
+	return gmobs[0]
+}
+
+// Each calls the function for all the game objects
+// selected by the query in the Z update order. The game
+// objects are selected before the first call so the
+// function may add, destroy and move game objects.
+func (query *Query) Each(fn func(gmob *GameObject) error) error {
+	for _, gmob := range query.GameObjects() {
+		err := fn(gmob)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanGameObjects selects the game objects
+// by visiting all the game objects of the scene.
+func (query *Query) scanGameObjects() []*GameObject {
+	gmobs := []*GameObject{}
+	query.scene.gmobs.VisitInOrder(func(key ZUpdateKey, gmob *GameObject) error {
+		if query.matches(gmob) {
+			gmobs = append(gmobs, gmob)
+		}
+
+		return nil
+	})
+
+	return gmobs
+}
+
+// matches returns true if the game
+// object satisfies all the conditions.
+func (query *Query) matches(gmob *GameObject) bool {
+	if gmob.destroyed {
+		return false
+	}
+
+	for _, typeID := range query.typeIDs {
+		if len(gmob.components[typeID]) <= 0 {
+			return false
+		}
+	}
+
+	for _, tag := range query.tags {
+		if !gmob.HasTag(tag) {
+			return false
+		}
+	}
+
+	if query.layerSet && !gmob.layer.Intersects(query.layer) {
+		return false
+	}
+
+	for _, predicate := range query.predicates {
+		if !predicate(gmob) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Query creates a new query to select
+// the game objects of the scene.
+func (scene *Scene) Query() *Query {
+	return &Query{
+		scene:      scene,
+		typeIDs:    []string{},
+		tags:       []string{},
+		predicates: []func(gmob *GameObject) bool{},
+	}
+}
+
+// FindGameObjectsWithComponents returns all the game
+// objects of the scene having components of all the
+// specified types in the Z update order.
+func (scene *Scene) FindGameObjectsWithComponents(typeIDs ...string) []*GameObject {
+	return scene.Query().WithComponents(typeIDs...).GameObjects()
+}
+
+// FindGameObjectsWithTag returns all the game objects
+// of the scene having the tag in the Z update order.
+func (scene *Scene) FindGameObjectsWithTag(tag string) []*GameObject {
+	return scene.Query().WithTags(tag).GameObjects()
+}
+
+// FindGameObjectsInLayer returns all the game objects
+// of the scene belonging to any layer of the mask
+// in the Z update order. No game objects belong
+// to LayerNone.
+func (scene *Scene) FindGameObjectsInLayer(layer LayerMask) []*GameObject {
+	return scene.Query().WithLayer(layer).GameObjects()
+}
+
+// indexGameObject adds all the components and
+// tags of the game object to the scene indices.
+func (scene *Scene) indexGameObject(gmob *GameObject) {
+	for typeID := range gmob.components {
+		scene.indexComponent(gmob, typeID)
+	}
+
+	for tag := range gmob.tags {
+		scene.indexTag(gmob, tag)
+	}
+}
+
+// unindexGameObject removes the game
+// object from the scene indices.
+func (scene *Scene) unindexGameObject(gmob *GameObject) {
+	// All the types and tags are checked as the
+	// game object components and tags could be
+	// removed after it was detached from the scene.
+	for typeID, index := range scene.componentIndex {
+		delete(index, gmob)
+
+		if len(index) <= 0 {
+			delete(scene.componentIndex, typeID)
+		}
+	}
+
+	for tag, index := range scene.tagIndex {
+		delete(index, gmob)
+
+		if len(index) <= 0 {
+			delete(scene.tagIndex, tag)
+		}
+	}
+}
+
+// indexComponent adds the game object to the
+// index of the component type if the game
+// object is placed on the scene.
+func (scene *Scene) indexComponent(gmob *GameObject, typeID string) {
+	if scene.gmobNameIndex[gmob.name] != gmob {
+		return
+	}
+
+	index, ok := scene.componentIndex[typeID]
+
+	if !ok {
+		index = map[*GameObject]struct{}{}
+		scene.componentIndex[typeID] = index
+	}
+
+	index[gmob] = struct{}{}
+}
+
+// unindexComponent removes the game object
+// from the index of the component type.
+func (scene *Scene) unindexComponent(gmob *GameObject, typeID string) {
+	index, ok := scene.componentIndex[typeID]
+
+	if !ok {
+		return
+	}
+
+	delete(index, gmob)
+
+	if len(index) <= 0 {
+		delete(scene.componentIndex, typeID)
+	}
+}
+
+// indexTag adds the game object to the index
+// of the tag if the game object is placed
+// on the scene.
+func (scene *Scene) indexTag(gmob *GameObject, tag string) {
+	if scene.gmobNameIndex[gmob.name] != gmob {
+		return
+	}
+
+	index, ok := scene.tagIndex[tag]
+
+	if !ok {
+		index = map[*GameObject]struct{}{}
+		scene.tagIndex[tag] = index
+	}
+
+	index[gmob] = struct{}{}
+}
+
+// unindexTag removes the game object
+// from the index of the tag.
+func (scene *Scene) unindexTag(gmob *GameObject, tag string) {
+	index, ok := scene.tagIndex[tag]
+
+	if !ok {
+		return
+	}
+
+	delete(index, gmob)
+
+	if len(index) <= 0 {
+		delete(scene.tagIndex, tag)
+	}
+}
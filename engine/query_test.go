@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func gameObjectNames(gmobs []*GameObject) []string {
+	names := make([]string, 0, len(gmobs))
+
+	for _, gmob := range gmobs {
+		names = append(names, gmob.Name())
+	}
+
+	return names
+}
+
+func TestQueryByComponents(t *testing.T) {
+	log := []string{}
+	scene := newTestScene(t, "query")
+
+	for _, entry := range []struct {
+		name    string
+		zUpd    float32
+		typeIDs []string
+	}{
+		{"player", 2, []string{"body", "input"}},
+		{"enemy", 1, []string{"body"}},
+		{"wall", 0, []string{"body"}},
+		{"camera", 3, []string{"input"}},
+	} {
+		gmob := NewGameObject(nil, entry.name, nil)
+
+		for _, typeID := range entry.typeIDs {
+			err := gmob.AddComponent(newProbe(typeID, &log), 0)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := scene.AddGameObject(gmob, entry.zUpd)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := gameObjectNames(scene.FindGameObjectsWithComponents("body"))
+
+	if !reflect.DeepEqual(names, []string{"wall", "enemy", "player"}) {
+		t.Fatalf("wrong game objects with bodies: %v", names)
+	}
+
+	names = gameObjectNames(scene.FindGameObjectsWithComponents("body", "input"))
+
+	if !reflect.DeepEqual(names, []string{"player"}) {
+		t.Fatalf("wrong game objects with bodies and input: %v", names)
+	}
+
+	names = gameObjectNames(scene.Query().Where(func(gmob *GameObject) bool {
+		return gmob.ComponentCount() == 1
+	}).GameObjects())
+
+	if !reflect.DeepEqual(names, []string{"wall", "enemy", "camera"}) {
+		t.Fatalf("wrong game objects with a single component: %v", names)
+	}
+
+	// Change the index and the order
+	// through the scene buffers.
+	camera := scene.FindGameObject("camera")
+	err := camera.AddComponent(newProbe("body", &log), 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.ChangeGameObjectZ("camera", -1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enemy := scene.FindGameObject("enemy")
+	err = scene.DestroyGameObject("enemy")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	added := NewGameObject(nil, "added", nil)
+	err = added.AddComponent(newProbe("body", &log), 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.AddGameObjectInRuntime(added, 5)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The destroyed game object is excluded at once
+	// and the other changes are applied on update.
+	names = gameObjectNames(scene.FindGameObjectsWithComponents("body"))
+
+	if !reflect.DeepEqual(names, []string{"wall", "player", "camera"}) {
+		t.Fatalf("wrong game objects before the update: %v", names)
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names = gameObjectNames(scene.FindGameObjectsWithComponents("body"))
+
+	if !reflect.DeepEqual(names, []string{"camera", "wall", "player", "added"}) {
+		t.Fatalf("wrong game objects after the update: %v", names)
+	}
+
+	player := scene.FindGameObject("player")
+	err = player.RemoveComponent(player.FindComponent("input"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gmobs := scene.FindGameObjectsWithComponents("input"); len(gmobs) != 1 {
+		t.Fatalf("the removed component is still indexed: %v", gameObjectNames(gmobs))
+	}
+
+	if _, ok := scene.componentIndex["body"][enemy]; ok {
+		t.Fatal("the destroyed game object is still indexed")
+	}
+}
+
+func TestQueryByTagsAndLayers(t *testing.T) {
+	scene := newTestScene(t, "tags")
+
+	for _, entry := range []struct {
+		name  string
+		zUpd  float32
+		tags  []string
+		layer LayerMask
+	}{
+		{"player", 2, []string{"actor", "friend"}, Layer(1)},
+		{"enemy", 1, []string{"actor"}, Layer(2)},
+		{"wall", 0, nil, Layer(3)},
+	} {
+		gmob := NewGameObject(nil, entry.name, nil)
+		gmob.SetLayer(entry.layer)
+
+		for _, tag := range entry.tags {
+			gmob.AddTag(tag)
+		}
+
+		err := scene.AddGameObject(gmob, entry.zUpd)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := gameObjectNames(scene.FindGameObjectsWithTag("actor"))
+
+	if !reflect.DeepEqual(names, []string{"enemy", "player"}) {
+		t.Fatalf("wrong game objects with the tag: %v", names)
+	}
+
+	names = gameObjectNames(scene.FindGameObjectsInLayer(Layer(1) | Layer(3)))
+
+	if !reflect.DeepEqual(names, []string{"wall", "player"}) {
+		t.Fatalf("wrong game objects in the layers: %v", names)
+	}
+
+	if gmobs := scene.FindGameObjectsInLayer(LayerNone); len(gmobs) != 0 {
+		t.Fatalf("game objects found in no layers: %v", gameObjectNames(gmobs))
+	}
+
+	names = gameObjectNames(scene.Query().WithTags("actor").
+		WithLayer(Layer(2)).GameObjects())
+
+	if !reflect.DeepEqual(names, []string{"enemy"}) {
+		t.Fatalf("wrong game objects with the tag in the layer: %v", names)
+	}
+
+	// The tags changed on the scene
+	// are reflected in the index.
+	wall := scene.FindGameObject("wall")
+	wall.AddTag("actor")
+	scene.FindGameObject("player").RemoveTag("actor")
+
+	names = gameObjectNames(scene.FindGameObjectsWithTag("actor"))
+
+	if !reflect.DeepEqual(names, []string{"wall", "enemy"}) {
+		t.Fatalf("wrong game objects after the tags changed: %v", names)
+	}
+
+	if !reflect.DeepEqual(wall.Tags(), []string{"actor"}) {
+		t.Fatalf("wrong tags: %v", wall.Tags())
+	}
+
+	err := scene.RemoveGameObject("wall")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := scene.tagIndex["actor"][wall]; ok {
+		t.Fatal("the removed game object is still indexed")
+	}
+}
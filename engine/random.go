@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"math/rand"
+	"time"
+)
+
+var (
+	// randomSeed is the seed of
+	// the engine random source.
+	randomSeed int64
+	// random is the random source to be used by
+	// the game logic for the game to be reproducible.
+	random *rand.Rand
+)
+
+func init() {
+	SetRandomSeed(time.Now().UnixNano())
+}
+
+// Random returns the random source of the engine.
+// The game logic should use it instead of the global
+// random functions so the game can be reproduced
+// by playing the recorded input back with the
+// same seed.
+//
+// The source is not safe for concurrent use and
+// must be used on the main thread only, e.g. by
+// components, systems and tasks. Other goroutines
+// should create their own sources.
+func Random() *rand.Rand {
+	return random
+}
+
+// RandomSeed returns the seed the
+// engine random source was created with.
+func RandomSeed() int64 {
+	return randomSeed
+}
+
+// SetRandomSeed recreates the engine
+// random source with the specified seed.
+// Should be called on the main thread
+// before the scenes are started.
+func SetRandomSeed(seed int64) {
+	randomSeed = seed
+	random = rand.New(rand.NewSource(seed))
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alacrity-engine/core/system"
+)
+
+// scriptedInput is a test input source
+// which presses the space on even frames.
+type scriptedInput struct {
+	frame *int
+}
+
+func (input scriptedInput) ButtonPressed(button system.Button) bool {
+	return button == system.KeySpace && *input.frame%2 == 0
+}
+
+// jumper is a test component which raises the
+// counter by a random value when the space is pressed.
+type jumper struct {
+	BaseComponent
+}
+
+func (j *jumper) TypeID() string {
+	return "engine__Jumper"
+}
+
+func (j *jumper) Update() error {
+	if system.ButtonPressed(system.KeySpace) {
+		j.GameObject().FindComponent(testCounterTypeID).(*counter).Value += Random().Intn(100)
+	}
+
+	return nil
+}
+
+func runReplayTest(t *testing.T, config RunConfig, frames int) []uint64 {
+	t.Helper()
+
+	scene := newTestScene(t, "replay")
+	gmob := NewGameObject(nil, "jumper", nil)
+
+	for _, comp := range []Component{&counter{}, &jumper{}} {
+		comp.SetActive(true)
+		err := gmob.AddComponent(comp, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := scene.AddGameObject(gmob, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = AddScene(scene)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		RemoveScene(scene.name)
+		currentSceneName = ""
+		system.SetInputSource(nil)
+	}()
+
+	hashes := []uint64{}
+	stateHash := config.StateHash
+	config.Headless = true
+	config.Clock = NewManualClock(time.Unix(0, 0))
+	config.StateHash = func(frame uint64, hash uint64) error {
+		hashes = append(hashes, hash)
+
+		if stateHash != nil {
+			return stateHash(frame, hash)
+		}
+
+		return nil
+	}
+
+	loop, err := NewLoop(config)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The scene is started after the loop
+	// seeds the random source.
+	err = scene.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < frames && !loop.stopped; i++ {
+		// The recorded delta time must be used
+		// instead of the one of the clock.
+		config.Clock.(*ManualClock).Advance(time.Duration(i+1) * time.Millisecond)
+		err = loop.Tick()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if loop.stopped && loop.inputInstalled {
+		t.Fatal("the input source is not reset by the stopped loop")
+	}
+
+	return hashes
+}
+
+func TestInputRecordingAndPlayback(t *testing.T) {
+	frame := 0
+	var record bytes.Buffer
+	recorder, err := system.NewInputRecorder(&record, scriptedInput{&frame}, 42)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded := runReplayTest(t, RunConfig{
+		InputRecorder: recorder,
+		StateHash: func(_ uint64, _ uint64) error {
+			frame++
+			return nil
+		},
+	}, 6)
+
+	// The random source is reseeded
+	// from the record on playback.
+	SetRandomSeed(0)
+	player, err := system.NewInputPlayer(&record)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	played := runReplayTest(t, RunConfig{
+		InputPlayer: player,
+	}, 10)
+
+	if !reflect.DeepEqual(recorded, played) {
+		t.Fatalf("the playback diverges: %v, %v", recorded, played)
+	}
+
+	if player.Seed() != 42 || player.Frames() != 6 {
+		t.Fatalf("wrong record: seed %d, %d frames", player.Seed(), player.Frames())
+	}
+}
+
+func TestRecordingRequiresUnstartedScenes(t *testing.T) {
+	scene := newTestScene(t, "started")
+	err := AddScene(scene)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		RemoveScene(scene.name)
+		currentSceneName = ""
+	}()
+
+	err = scene.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var record bytes.Buffer
+	recorder, err := system.NewInputRecorder(&record, scriptedInput{new(int)}, 42)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewLoop(RunConfig{
+		Headless:      true,
+		InputRecorder: recorder,
+	})
+
+	if err == nil {
+		t.Fatal("no error for the scene started before seeding")
+	}
+}
@@ -0,0 +1,261 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/system/collections"
+	"github.com/alacrity-engine/core/tasking"
+)
+
+const (
+	// sceneLoadingDefinitionProgress is the progress
+	// of the scene loading after the definition is read.
+	sceneLoadingDefinitionProgress = 5
+	// sceneLoadingBuildProgress is the progress
+	// of the scene loading after all the game
+	// objects are created.
+	sceneLoadingBuildProgress = 90
+	// sceneLoadingPointersProgress is the progress
+	// of the scene loading after all the pointers
+	// are resolved.
+	sceneLoadingPointersProgress = 95
+)
+
+// sceneLoading is the state of the process
+// of loading the scene from the resource file.
+type sceneLoading struct {
+	process       *tasking.AsynchronousProcess
+	resourceFile  string
+	sceneID       string
+	gmobsProducer collections.UnrestrictedSortedDictionaryProducer[ZUpdateKey, *GameObject]
+	params        sceneLoadingParameters
+	scene         *Scene
+	sceneDef      *definitions.SceneDefinition
+	builder       *prefabBuilder
+	nextRoot      int
+	totalGmobs    int
+}
+
+// step performs the next step of the scene loading:
+// reads the definition, builds a single root transform,
+// resolves the pointers or adds the game objects to the
+// scene. It returns false when the loading is over.
+func (loading *sceneLoading) step() (bool, error) {
+	var err error
+
+	switch {
+	case loading.sceneDef == nil:
+		err = loading.readDefinition()
+
+	case loading.nextRoot < len(loading.sceneDef.Transforms):
+		err = loading.buildRoot()
+
+	default:
+		err = loading.finish()
+
+		if err == nil {
+			return false, nil
+		}
+	}
+
+	if err != nil {
+		loading.fail(err)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// readDefinition creates the scene, opens its
+// resource file and reads the scene definition.
+func (loading *sceneLoading) readDefinition() error {
+	scene, err := NewScene(loading.sceneID, loading.gmobsProducer)
+
+	if err != nil {
+		return err
+	}
+
+	loading.scene = scene
+	loader, err := scene.GetResourceLoader(loading.resourceFile)
+
+	if err != nil {
+		return err
+	}
+
+	sceneDef, err := loader.LoadSceneDefinition(loading.sceneID)
+
+	if err != nil {
+		return err
+	}
+
+	if sceneDef.Name != "" {
+		scene.name = sceneDef.Name
+	}
+
+	if loading.params.setup != nil {
+		err = loading.params.setup(scene)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	options := append([]InstantiationOption{
+		InstantiationOptionWithResourceLoader(loader),
+	}, loading.params.instantiationOptions...)
+	builder, err := newPrefabBuilder(scene, options...)
+
+	if err != nil {
+		return err
+	}
+
+	loading.builder = builder
+	loading.sceneDef = sceneDef
+	loading.totalGmobs = countGameObjects(sceneDef.Transforms)
+	loading.process.SetProgress(sceneLoadingDefinitionProgress)
+
+	return nil
+}
+
+// buildRoot creates the game objects of the next
+// root transform and reports the progress.
+func (loading *sceneLoading) buildRoot() error {
+	transformDef := loading.sceneDef.Transforms[loading.nextRoot]
+	loading.nextRoot++
+
+	if transformDef == nil {
+		return nil
+	}
+
+	_, err := loading.builder.buildTransform(transformDef, nil)
+
+	if err != nil {
+		return err
+	}
+
+	progress := sceneLoadingBuildProgress
+
+	if loading.totalGmobs > 0 {
+		progress = sceneLoadingDefinitionProgress +
+			(sceneLoadingBuildProgress-sceneLoadingDefinitionProgress)*
+				len(loading.builder.gmobs)/loading.totalGmobs
+	}
+
+	loading.process.SetProgress(progress)
+
+	return nil
+}
+
+// finish resolves the pointers, adds all the game
+// objects to the scene and completes the process.
+func (loading *sceneLoading) finish() error {
+	loading.process.SetProgress(sceneLoadingBuildProgress)
+	err := loading.builder.resolvePointers()
+
+	if err != nil {
+		return err
+	}
+
+	loading.process.SetProgress(sceneLoadingPointersProgress)
+
+	for _, built := range loading.builder.gmobs {
+		err := loading.scene.AddGameObject(built.gmob, built.zUpd)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	loading.process.SetResult(loading.scene)
+	loading.process.SetProgress(100)
+
+	return nil
+}
+
+// fail removes the sprites of the built game objects
+// from their canvases, releases the scene resources
+// and completes the process with the error.
+func (loading *sceneLoading) fail(err error) {
+	if loading.builder != nil {
+		loading.builder.discard()
+	}
+
+	if loading.scene != nil {
+		for loaderID, loader := range loading.scene.resourceLoaders {
+			loader.Close()
+			delete(loading.scene.resourceLoaders, loaderID)
+		}
+	}
+
+	loading.process.SetError(fmt.Errorf(
+		"cannot load scene '%s': %w", loading.sceneID, err))
+	loading.process.SetProgress(100)
+}
+
+// countGameObjects returns the number of game
+// objects in all the transform hierarchies.
+func countGameObjects(transformDefs []*definitions.TransformDefinition) int {
+	count := 0
+
+	for _, transformDef := range transformDefs {
+		if transformDef == nil {
+			continue
+		}
+
+		if transformDef.Gmob != nil {
+			count++
+		}
+
+		count += countGameObjects(transformDef.Children)
+	}
+
+	return count
+}
+
+// StartLoadingSceneFromResources starts an asynchronous
+// process of loading the scene from the gob-encoded scene
+// definition stored in the 'scenes' bucket of the resource
+// file under the specified ID.
+//
+// The scene is loaded by the task started in the task manager,
+// one root transform per update, so the graphical resources
+// are created on the main thread. The result of the process
+// is the new *Scene which should be added with AddScene.
+// The resource file is opened by the scene and closed
+// when the scene is unloaded.
+func StartLoadingSceneFromResources(
+	taskMgr *tasking.TaskManager, resourceFile, sceneID string,
+	gmobsDictProducer collections.UnrestrictedSortedDictionaryProducer[ZUpdateKey, *GameObject],
+	options ...SceneLoadingOption,
+) (*tasking.AsynchronousProcess, error) {
+	var params sceneLoadingParameters
+
+	for i := 0; i < len(options); i++ {
+		option := options[i]
+		err := option(&params)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	process := tasking.NewAsynchronousProcess(
+		fmt.Sprintf("load scene '%s'", sceneID))
+	loading := &sceneLoading{
+		process:       process,
+		resourceFile:  resourceFile,
+		sceneID:       sceneID,
+		gmobsProducer: gmobsDictProducer,
+		params:        params,
+	}
+
+	err := taskMgr.StartTask(
+		fmt.Sprintf("load-scene-%s", sceneID), loading.step)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return process, nil
+}
@@ -0,0 +1,167 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/gob"
+	"path/filepath"
+	"testing"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/tasking"
+	bolt "go.etcd.io/bbolt"
+)
+
+func writeTestSceneFile(t *testing.T, sceneDef *definitions.SceneDefinition) string {
+	t.Helper()
+
+	fname := filepath.Join(t.TempDir(), "resources.db")
+	db, err := bolt.Open(fname, 0666, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(sceneDef)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		buck, err := tx.CreateBucketIfNotExists([]byte("scenes"))
+
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte("level"), buf.Bytes())
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fname
+}
+
+func TestLoadSceneFromResources(t *testing.T) {
+	fname := writeTestSceneFile(t, &definitions.SceneDefinition{
+		Name: "level",
+		Transforms: []*definitions.TransformDefinition{
+			{Gmob: counterDefinition("first", 1, "third")},
+			{Gmob: counterDefinition("second", 2, "")},
+			{Gmob: counterDefinition("third", 3, "first")},
+		},
+	})
+	taskMgr := tasking.NewTaskManager()
+	setupCalled := false
+
+	process, err := StartLoadingSceneFromResources(taskMgr, fname, "level",
+		newTestGameObjectsProducer(t), SceneLoadingOptionWithSetup(
+			func(scene *Scene) error {
+				setupCalled = true
+				return nil
+			}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress := []int{}
+
+	for i := 0; i < 10 && process.CurrentProgress() < 100; i++ {
+		err = taskMgr.Update()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+	notifications:
+		for {
+			select {
+			case value := <-process.ProgressNotifier():
+				progress = append(progress, value)
+
+			default:
+				break notifications
+			}
+		}
+	}
+
+	processErr, err := process.Error()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processErr != nil {
+		t.Fatal(processErr)
+	}
+
+	for i := 1; i < len(progress); i++ {
+		if progress[i] < progress[i-1] {
+			t.Fatalf("the progress decreases: %v", progress)
+		}
+	}
+
+	// The definition, three game objects
+	// and the pointers are reported.
+	if len(progress) < 5 || progress[len(progress)-1] != 100 {
+		t.Fatalf("wrong progress: %v", progress)
+	}
+
+	if !setupCalled {
+		t.Fatal("the setup function is not called")
+	}
+
+	result, err := process.Result()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scene := result.(*Scene)
+	defer scene.unload()
+
+	first := scene.FindGameObject("first")
+	third := scene.FindGameObject("third")
+
+	if first == nil || third == nil || scene.FindGameObject("second") == nil {
+		t.Fatal("the game objects are not loaded")
+	}
+
+	if first.FindComponent(testCounterTypeID).(*counter).Target != third {
+		t.Fatal("the pointer is not resolved")
+	}
+}
+
+func TestLoadMissingSceneFromResources(t *testing.T) {
+	fname := writeTestSceneFile(t, &definitions.SceneDefinition{})
+	taskMgr := tasking.NewTaskManager()
+
+	process, err := StartLoadingSceneFromResources(taskMgr, fname, "missing",
+		newTestGameObjectsProducer(t))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = taskMgr.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processErr, err := process.Error()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processErr == nil {
+		t.Fatal("no error for the missing scene")
+	}
+}
@@ -5,9 +5,9 @@ import (
 	"math"
 	"path/filepath"
 
-	cmath "github.com/alacrity-engine/core/math"
 	"github.com/alacrity-engine/core/render"
 	"github.com/alacrity-engine/core/resources"
+	"github.com/alacrity-engine/core/system"
 	"github.com/alacrity-engine/core/system/collections"
 	"github.com/alacrity-engine/core/tasking"
 )
@@ -19,26 +19,56 @@ import (
 // standard collections (maps, slices)
 // with pooled custom counetrparts.
 
+// defaultFixedTimestep is the time (in seconds)
+// between two fixed updates of a new scene.
+const defaultFixedTimestep = 1.0 / 60.0
+
 // Scene is a collection of game objects
 // to be updated and drawn.
 type (
 	Scene struct {
 		name              string
-		gmobs             collections.UnrestrictedSortedDictionary[cmath.Fixed, map[*GameObject]struct{}]
+		gmobs             collections.UnrestrictedSortedDictionary[ZUpdateKey, *GameObject]
+		gmobsProducer     collections.UnrestrictedSortedDictionaryProducer[ZUpdateKey, *GameObject]
+		staleGmobs        []collections.UnrestrictedSortedDictionary[ZUpdateKey, *GameObject]
+		visiting          int
 		gmobNameIndex     map[string]*GameObject
-		addBuffer         map[*GameObject]float32
-		destructionBuffer map[string]struct{}
+		componentIndex    map[string]map[*GameObject]struct{}
+		tagIndex          map[string]map[*GameObject]struct{}
+		addBuffer         []bufferedGameObject
+		addBufferIndex    map[*GameObject]int
+		destructionBuffer []*GameObject
 		changeZBuffer     []changeZ
-		systems           map[string]System
+		systems           map[string]*systemEntry
+		systemOrder       []*systemEntry
+		lastSystemSeq     uint64
+		lastUpdateSeq     uint64
+		events            *eventBus
 		taskMgr           *tasking.TaskManager
 		layout            *render.Layout
+		ownLayout         *render.Layout
 		resourceLoaders   map[string]*resources.ResourceLoader
+		fixedTimestep     float64
+		fixedAccumulator  float64
+		moveBuffer        []moveGameObject
+		additive          bool
+		updateOrder       int
 	}
 
 	changeZ struct {
 		gmobName string
 		targetZ  float32
 	}
+
+	bufferedGameObject struct {
+		gmob *GameObject
+		zUpd float32
+	}
+
+	moveGameObject struct {
+		gmob   *GameObject
+		target *Scene
+	}
 )
 
 // Name returns the name of the scene.
@@ -51,6 +81,27 @@ func (scene *Scene) TaskManager() *tasking.TaskManager {
 	return scene.taskMgr
 }
 
+// FixedTimestep returns the time (in seconds)
+// between two fixed updates of the scene.
+func (scene *Scene) FixedTimestep() float64 {
+	return scene.fixedTimestep
+}
+
+// SetFixedTimestep sets the time (in seconds)
+// between two fixed updates of the scene.
+// The game loop accumulates the passed time
+// with the timestep of the active scene for
+// all the current scenes.
+func (scene *Scene) SetFixedTimestep(timestep float64) error {
+	if timestep <= 0 {
+		return fmt.Errorf("the fixed timestep must be positive, got %f", timestep)
+	}
+
+	scene.fixedTimestep = timestep
+
+	return nil
+}
+
 // DrawLayout returns the draw layout og the scene.
 func (scene *Scene) DrawLayout() *render.Layout {
 	return scene.layout
@@ -103,18 +154,21 @@ func (scene *Scene) GetResourceLoader(fname string) (*resources.ResourceLoader,
 
 // FindSystem returns the system with the specified name.
 func (scene *Scene) FindSystem(name string) (System, error) {
-	system, exists := scene.systems[name]
+	entry, exists := scene.systems[name]
 
 	if !exists {
 		return nil, fmt.Errorf("scene '%s' has no system '%s'",
 			scene.name, name)
 	}
 
-	return system, nil
+	return entry.system, nil
 }
 
 // AddSystem adds the system to the scene and assigns the name to it.
-func (scene *Scene) AddSystem(name string, system System) error {
+// The system is started on the next start or update of the scene.
+// All the dependencies of the system must be added to the scene
+// first, otherwise the system is rejected.
+func (scene *Scene) AddSystem(name string, system System, options ...SystemOption) error {
 	_, err := scene.FindSystem(name)
 
 	if err == nil {
@@ -122,39 +176,200 @@ func (scene *Scene) AddSystem(name string, system System) error {
 			scene.name, name)
 	}
 
-	scene.systems[name] = system
+	var params systemParameters
+
+	for i := 0; i < len(options); i++ {
+		option := options[i]
+		err := option(&params)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, dependency := range params.dependencies {
+		if dependency == name {
+			return fmt.Errorf("system '%s' cannot depend on itself", name)
+		}
+
+		if _, ok := scene.systems[dependency]; !ok {
+			return fmt.Errorf("system '%s' depends on absent system '%s'",
+				name, dependency)
+		}
+	}
+
+	entry := &systemEntry{
+		name:         name,
+		system:       system,
+		order:        params.order,
+		dependencies: params.dependencies,
+		seq:          scene.lastSystemSeq + 1,
+	}
+	scene.systems[name] = entry
+	order, err := sortSystems(scene.systems)
+
+	if err != nil {
+		delete(scene.systems, name)
+		return fmt.Errorf("cannot add system '%s': %w", name, err)
+	}
+
+	scene.lastSystemSeq = entry.seq
+	scene.systemOrder = order
 
 	return nil
 }
 
 // RemoveSystem removes the system from the scene by its name.
+// The system cannot be removed while other systems depend on it.
 func (scene *Scene) RemoveSystem(name string) error {
-	_, err := scene.FindSystem(name)
+	entry, exists := scene.systems[name]
 
-	if err != nil {
+	if !exists {
 		return fmt.Errorf("scene '%s' doesn't have system '%s'",
 			scene.name, name)
 	}
 
+	for _, other := range scene.systems {
+		for _, dependency := range other.dependencies {
+			if dependency == name {
+				return fmt.Errorf("system '%s' depends on system '%s'",
+					other.name, name)
+			}
+		}
+	}
+
+	if sys, ok := entry.system.(DestroyableSystem); ok {
+		err := sys.Destroy()
+
+		if err != nil {
+			return err
+		}
+	}
+
 	delete(scene.systems, name)
+	scene.systemOrder = nil
 
 	return nil
 }
 
-// Start starts components of all
-// the game objects on the scene.
-func (scene *Scene) Start() error {
-	err := scene.gmobs.VisitInOrder(func(key cmath.Fixed, gmobs map[*GameObject]struct{}) error {
-		for gmob := range gmobs {
-			err := gmob.Start()
+// orderedSystems returns the systems of
+// the scene in the order of their execution.
+func (scene *Scene) orderedSystems() ([]*systemEntry, error) {
+	if scene.systemOrder != nil {
+		return scene.systemOrder, nil
+	}
+
+	order, err := sortSystems(scene.systems)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scene.systemOrder = order
+
+	return order, nil
+}
+
+// startSystems starts all the systems
+// that haven't been started yet.
+func (scene *Scene) startSystems() error {
+	order, err := scene.orderedSystems()
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range order {
+		if entry.started {
+			continue
+		}
+
+		entry.started = true
+
+		if sys, ok := entry.system.(StartableSystem); ok {
+			err := sys.Start()
 
 			if err != nil {
 				return err
 			}
 		}
+	}
 
-		return nil
-	})
+	return nil
+}
+
+// updateSystems updates all the systems.
+func (scene *Scene) updateSystems(deltaTime float64) error {
+	order, err := scene.orderedSystems()
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range order {
+		if sys, ok := entry.system.(UpdatableSystem); ok {
+			err := sys.Update(deltaTime)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// lateUpdateSystems late updates all the systems.
+func (scene *Scene) lateUpdateSystems() error {
+	order, err := scene.orderedSystems()
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range order {
+		if sys, ok := entry.system.(LateUpdatableSystem); ok {
+			err := sys.LateUpdate()
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// destroySystems destroys all the systems in
+// the reverse order and removes them from the scene.
+func (scene *Scene) destroySystems() error {
+	order, err := scene.orderedSystems()
+
+	if err != nil {
+		return err
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		if sys, ok := order[i].system.(DestroyableSystem); ok {
+			err := sys.Destroy()
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	scene.systems = map[string]*systemEntry{}
+	scene.systemOrder = nil
+
+	return nil
+}
+
+// Start starts the systems and the
+// components of all the game objects
+// on the scene and makes the scene active.
+func (scene *Scene) Start() error {
+	err := scene.start()
 
 	if err != nil {
 		return err
@@ -165,10 +380,55 @@ func (scene *Scene) Start() error {
 	return nil
 }
 
+// start starts the systems and the components
+// of all the game objects on the scene.
+func (scene *Scene) start() error {
+	err := scene.startSystems()
+
+	if err != nil {
+		return err
+	}
+
+	return scene.visitGameObjects(func(gmob *GameObject) error {
+		return gmob.Start()
+	})
+}
+
 // Update calls update method on all
 // game objects of the scene.
+//
+// Fixed updates are performed with the
+// time obtained from system.DeltaTime.
+// The game loop accumulates the time
+// on its own and doesn't call Update.
 func (scene *Scene) Update() error {
-	err := scene.removeDestroyedGameObjects()
+	err := scene.flushBuffers()
+
+	if err != nil {
+		return err
+	}
+
+	err = scene.fixedUpdate(system.DeltaTime())
+
+	if err != nil {
+		return err
+	}
+
+	return scene.frameUpdate()
+}
+
+// flushBuffers moves the game objects to the other
+// scenes, removes the destroyed game objects, changes
+// Z update coordinates, adds the buffered game
+// objects to the scene and delivers the queued events.
+func (scene *Scene) flushBuffers() error {
+	err := scene.moveGameObjects()
+
+	if err != nil {
+		return err
+	}
+
+	err = scene.removeDestroyedGameObjects()
 
 	if err != nil {
 		return err
@@ -186,17 +446,31 @@ func (scene *Scene) Update() error {
 		return err
 	}
 
-	// Update all the game objects.
-	err = scene.gmobs.VisitInOrder(func(key cmath.Fixed, gmobs map[*GameObject]struct{}) error {
-		for gmob := range gmobs {
-			err = gmob.Update()
+	return scene.events.deliverQueuedEvents()
+}
 
-			if err != nil {
-				return err
-			}
-		}
+// frameUpdate updates the systems and all the
+// game objects, performs the next iteration of
+// the tasks and then late updates the game
+// objects and the systems.
+func (scene *Scene) frameUpdate() error {
+	// Start the systems added after
+	// the scene was started.
+	err := scene.startSystems()
 
-		return nil
+	if err != nil {
+		return err
+	}
+
+	err = scene.updateSystems(system.DeltaTime())
+
+	if err != nil {
+		return err
+	}
+
+	// Update all the game objects.
+	err = scene.visitGameObjects(func(gmob *GameObject) error {
+		return gmob.Update()
 	})
 
 	if err != nil {
@@ -210,25 +484,123 @@ func (scene *Scene) Update() error {
 		return err
 	}
 
+	// Update the game objects that
+	// depend on the results of Update.
+	err = scene.visitGameObjects(func(gmob *GameObject) error {
+		return gmob.LateUpdate()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return scene.lateUpdateSystems()
+}
+
+// fixedUpdate accumulates the time passed since
+// the last frame and performs as many fixed updates
+// of the game objects as the accumulated time fits.
+func (scene *Scene) fixedUpdate(deltaTime float64) error {
+	scene.fixedAccumulator += deltaTime
+
+	for scene.fixedAccumulator >= scene.fixedTimestep {
+		err := scene.fixedStep()
+
+		if err != nil {
+			return err
+		}
+
+		scene.fixedAccumulator -= scene.fixedTimestep
+	}
+
+	return nil
+}
+
+// fixedStep performs a single fixed
+// update of all the game objects.
+func (scene *Scene) fixedStep() error {
+	return scene.visitGameObjects(func(gmob *GameObject) error {
+		return gmob.FixedUpdate()
+	})
+}
+
+// draw sets the sprites of all the game objects
+// which should be drawn to be drawn on their
+// canvases in the Z update order.
+func (scene *Scene) draw() error {
+	return scene.visitGameObjects(func(gmob *GameObject) error {
+		return gmob.Draw()
+	})
+}
+
+// visitGameObjects calls the function for all the
+// game objects of the scene in the Z update order.
+//
+// If the scene is unloaded while its game objects are
+// visited, the old Z update index is disposed only
+// after the outermost visit is over.
+func (scene *Scene) visitGameObjects(visit func(gmob *GameObject) error) error {
+	scene.visiting++
+	err := scene.gmobs.VisitInOrder(func(key ZUpdateKey, gmob *GameObject) error {
+		return visit(gmob)
+	})
+	scene.visiting--
+
+	if err != nil {
+		return err
+	}
+
+	if scene.visiting > 0 {
+		return nil
+	}
+
+	return scene.disposeStaleGameObjects()
+}
+
+// disposeStaleGameObjects disposes all the Z update
+// indices left after the scene was unloaded.
+func (scene *Scene) disposeStaleGameObjects() error {
+	for _, gmobs := range scene.staleGmobs {
+		err := scene.gmobsProducer.Dispose(gmobs)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	scene.staleGmobs = nil
+
 	return nil
 }
 
 // insertGameObject inserts the game object
-// into the sorted Z-buffer using binary search.
+// into the sorted Z-buffer.
+//
+// Game objects with the same Z are updated in the
+// order they were first inserted into the scene.
 func (scene *Scene) insertGameObject(gmob *GameObject, zUpd float32) error {
-	// Use binary search to insert the game
-	// object into the Z-sorted update buffer.
-	err := scene.gmobs.AddOrUpdate(cmath.FixedFromFloat32(zUpd),
-		map[*GameObject]struct{}{gmob: {}},
-		func(gmobs map[*GameObject]struct{}) (map[*GameObject]struct{}, error) {
-			gmobs[gmob] = struct{}{}
-			return gmobs, nil
-		})
+	// The sequence number and the Z update
+	// coordinate are assigned only after
+	// the game object is inserted, so the
+	// failed insertion leaves them intact.
+	seq := gmob.updateSeq
+	newSeq := gmob.scene != scene
+
+	if newSeq {
+		seq = scene.lastUpdateSeq + 1
+	}
+
+	err := scene.gmobs.Add(newZUpdateKey(zUpd, seq), gmob)
 
 	if err != nil {
 		return err
 	}
 
+	if newSeq {
+		scene.lastUpdateSeq = seq
+	}
+
+	gmob.updateSeq = seq
 	gmob.zUpdate = zUpd
 
 	return nil
@@ -237,16 +609,18 @@ func (scene *Scene) insertGameObject(gmob *GameObject, zUpd float32) error {
 // removeGameObject deletes the game
 // object from the Z update index.
 func (scene *Scene) removeGameObject(gmob *GameObject) error {
-	err := scene.gmobs.Update(cmath.FixedFromFloat32(gmob.zUpdate),
-		func(gmobs map[*GameObject]struct{}, found bool) (map[*GameObject]struct{}, error) {
-			if !found {
-				return nil, RaiseErrorNoGameObjectOnScene(scene, gmob.name)
-			}
+	key := gmob.zUpdateKey()
+	_, found, err := scene.gmobs.Search(key)
+
+	if err != nil {
+		return err
+	}
 
-			delete(gmobs, gmob)
+	if !found {
+		return RaiseErrorNoGameObjectOnScene(scene, gmob.name)
+	}
 
-			return gmobs, nil
-		})
+	err = scene.gmobs.Remove(key)
 
 	if err != nil {
 		return err
@@ -289,47 +663,46 @@ func (scene *Scene) placeGameObjects() error {
 // addBufferedGameObjects adds all the buffered
 // game objects to the scene.
 func (scene *Scene) addBufferedGameObjects() error {
+	// The game objects added while the buffered
+	// ones are started are added on the next update.
+	buffer := scene.addBuffer
+	scene.addBuffer = []bufferedGameObject{}
+	scene.addBufferIndex = map[*GameObject]int{}
+
 	// Add all the game objects from the buffer
+	// in the order they were set to be added
 	// and start them all.
-	for gmob, zUpd := range scene.addBuffer {
-		err := scene.AddGameObject(gmob, zUpd)
+	for _, buffered := range buffer {
+		gmob := buffered.gmob
 
-		if err != nil {
-			return err
+		// The game object could be destroyed
+		// along with its parent before it's added.
+		if gmob.destroyed {
+			continue
 		}
 
-		err = gmob.Start()
+		err := scene.AddGameObject(gmob, buffered.zUpd)
 
 		if err != nil {
 			return err
 		}
-	}
 
-	scene.addBuffer = map[*GameObject]float32{}
-
-	return nil
-}
+		err = gmob.Start()
 
-// findDestroyedGameObject the game object with
-// the specified name marked as destroyed.
-func (scene *Scene) findDestroyedGameObject(name string) *GameObject {
-	if gmob, ok := scene.gmobNameIndex[name]; ok {
-		if gmob.destroyed {
-			return gmob
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-// removeDestroyedGameObject removes the game object
-// with the specified name marked as destroyed.
-func (scene *Scene) removeDestroyedGameObject(name string) error {
-	gmob := scene.findDestroyedGameObject(name)
-
-	if gmob == nil {
+// removeDestroyedGameObject removes the
+// game object marked as destroyed.
+func (scene *Scene) removeDestroyedGameObject(gmob *GameObject) error {
+	if !gmob.destroyed || scene.gmobNameIndex[gmob.name] != gmob {
 		return fmt.Errorf("scene '%s' doesn't have destroyed game object '%s'",
-			scene.name, name)
+			scene.name, gmob.name)
 	}
 
 	err := scene.removeGameObject(gmob)
@@ -339,6 +712,7 @@ func (scene *Scene) removeDestroyedGameObject(name string) error {
 	}
 
 	delete(scene.gmobNameIndex, gmob.name)
+	scene.unindexGameObject(gmob)
 
 	return nil
 }
@@ -349,7 +723,9 @@ func (scene *Scene) removeDestroyedGameObject(name string) error {
 // ATTENTION: this method must not be called from
 // any ecs.Component. Call it after scene.Update().
 func (scene *Scene) removeDestroyedGameObjects() error {
-	for gmob := range scene.destructionBuffer {
+	// The game objects are removed in
+	// the order they were destroyed.
+	for _, gmob := range scene.destructionBuffer {
 		err := scene.removeDestroyedGameObject(gmob)
 
 		if err != nil {
@@ -357,7 +733,7 @@ func (scene *Scene) removeDestroyedGameObjects() error {
 		}
 	}
 
-	scene.destructionBuffer = map[string]struct{}{}
+	scene.destructionBuffer = []*GameObject{}
 
 	return nil
 }
@@ -375,12 +751,25 @@ func (scene *Scene) FindGameObject(name string) *GameObject {
 	}
 }
 
+// FindGameObjectByHandle finds the game object
+// on the scene by its handle. If the handle is stale
+// or the game object is not on the scene, nil is returned.
+func (scene *Scene) FindGameObjectByHandle(handle Handle) *GameObject {
+	gmob := handle.GameObject()
+
+	if gmob == nil || scene.gmobNameIndex[gmob.name] != gmob {
+		return nil
+	}
+
+	return gmob
+}
+
 // findGameObjectInAdded searches for a game object
 // with the specified name in the buffer where game objects
 // set to be added reside.
 func (scene *Scene) findGameObjectInAdded(gmob *GameObject) (bool, float32) {
-	if zUpd, ok := scene.addBuffer[gmob]; ok {
-		return true, zUpd
+	if i, ok := scene.addBufferIndex[gmob]; ok {
+		return true, scene.addBuffer[i].zUpd
 	}
 
 	return false, float32(math.NaN())
@@ -409,8 +798,15 @@ func (scene *Scene) AddGameObject(gmob *GameObject, zUpd float32) error {
 	}
 
 	scene.gmobNameIndex[gmob.name] = gmob
+	scene.indexGameObject(gmob)
 	gmob.SetScene(scene)
 
+	// The game object moved from
+	// the other scene keeps its handle.
+	if gmob.handle.GameObject() != gmob {
+		gmob.handle = allocateHandle(gmob)
+	}
+
 	return nil
 }
 
@@ -430,7 +826,11 @@ func (scene *Scene) AddGameObjectInRuntime(gmob *GameObject, zUpd float32) error
 			gmob.name)
 	}
 
-	scene.addBuffer[gmob] = zUpd
+	scene.addBufferIndex[gmob] = len(scene.addBuffer)
+	scene.addBuffer = append(scene.addBuffer, bufferedGameObject{
+		gmob: gmob,
+		zUpd: zUpd,
+	})
 
 	return nil
 }
@@ -452,6 +852,8 @@ func (scene *Scene) ChangeGameObjectZ(name string, zUpd float32) error {
 }
 
 // RemoveGameObject removes the game object from the scene.
+// All the event subscriptions of the game object and its
+// components on the scene are cancelled.
 func (scene *Scene) RemoveGameObject(name string) error {
 	gmob := scene.FindGameObject(name)
 
@@ -466,12 +868,181 @@ func (scene *Scene) RemoveGameObject(name string) error {
 	}
 
 	delete(scene.gmobNameIndex, name)
+	scene.unindexGameObject(gmob)
+	scene.events.unsubscribeGameObject(gmob)
+	releaseHandle(gmob.handle)
+	gmob.handle = Handle{}
+
+	return nil
+}
+
+// MoveGameObjectTo moves the game object along with all
+// its descendants to the other scene. If the game objects
+// of the scene are being updated, the game objects are
+// moved on the next update of the scene. The game objects
+// keep their Z update coordinates, and the descendants
+// set to be added to the scene are added to the other
+// scene instead.
+func (scene *Scene) MoveGameObjectTo(name string, other *Scene) error {
+	gmob := scene.FindGameObject(name)
+
+	if gmob == nil {
+		return RaiseErrorNoGameObjectOnScene(scene, name)
+	}
+
+	if other == scene {
+		return fmt.Errorf("game object '%s' is already on scene '%s'",
+			name, scene.name)
+	}
+
+	err := other.checkNamesFree(scene.subtree(gmob))
+
+	if err != nil {
+		return err
+	}
+
+	if scene.visiting > 0 {
+		scene.moveBuffer = append(scene.moveBuffer, moveGameObject{
+			gmob:   gmob,
+			target: other,
+		})
+
+		return nil
+	}
+
+	return scene.moveGameObject(gmob, other)
+}
+
+// subtree returns the game object and all its descendants
+// placed on the scene or set to be added to it, parents
+// preceding their children.
+func (scene *Scene) subtree(gmob *GameObject) []*GameObject {
+	subtree := []*GameObject{gmob}
+	queue := []*GameObject{gmob}
+
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+
+		for _, child := range parent.Children() {
+			queue = append(queue, child)
+
+			if child.scene == scene {
+				subtree = append(subtree, child)
+			} else if _, ok := scene.addBufferIndex[child]; ok {
+				subtree = append(subtree, child)
+			}
+		}
+	}
+
+	return subtree
+}
+
+// moveGameObject removes the game object and all
+// its descendants from the scene and adds them
+// to the other scene along with their event
+// subscriptions. The names of all the game objects
+// are checked before any of them is moved.
+func (scene *Scene) moveGameObject(gmob *GameObject, other *Scene) error {
+	subtree := scene.subtree(gmob)
+	err := other.checkNamesFree(subtree)
+
+	if err != nil {
+		return err
+	}
+
+	for _, member := range subtree {
+		for _, sub := range scene.events.takeGameObject(member) {
+			other.events.subscribe(sub)
+		}
+
+		if member.scene != scene {
+			zUpd := scene.takeFromAddBuffer(member)
+			err := other.AddGameObjectInRuntime(member, zUpd)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		err := scene.removeGameObject(member)
+
+		if err != nil {
+			return err
+		}
+
+		delete(scene.gmobNameIndex, member.name)
+		scene.unindexGameObject(member)
+		member.SetScene(nil)
+
+		if other.visiting > 0 {
+			err = other.AddGameObjectInRuntime(member, member.zUpdate)
+		} else {
+			err = other.AddGameObject(member, member.zUpdate)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// takeFromAddBuffer removes the game object from the
+// buffer of the game objects set to be added to the
+// scene and returns its Z update coordinate.
+func (scene *Scene) takeFromAddBuffer(gmob *GameObject) float32 {
+	ind, ok := scene.addBufferIndex[gmob]
+
+	if !ok {
+		return 0
+	}
+
+	zUpd := scene.addBuffer[ind].zUpd
+	buffer := make([]bufferedGameObject, 0, len(scene.addBuffer)-1)
+	buffer = append(buffer, scene.addBuffer[:ind]...)
+	buffer = append(buffer, scene.addBuffer[ind+1:]...)
+	scene.addBuffer = buffer
+	delete(scene.addBufferIndex, gmob)
+
+	for i := ind; i < len(scene.addBuffer); i++ {
+		scene.addBufferIndex[scene.addBuffer[i].gmob] = i
+	}
+
+	return zUpd
+}
+
+// moveGameObjects moves all the buffered
+// game objects to their target scenes.
+func (scene *Scene) moveGameObjects() error {
+	for _, move := range scene.moveBuffer {
+		// The game object could be
+		// destroyed after the request.
+		if move.gmob.destroyed {
+			continue
+		}
+
+		err := scene.moveGameObject(move.gmob, move.target)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	scene.moveBuffer = []moveGameObject{}
 
 	return nil
 }
 
 // DestroyGameObject destroys the game object, i.e.
 // deactivates all its components and stops drawing it.
+// All the descendants of the game object are destroyed
+// as well. The resources loaded for the game object by
+// the engine on instantiation are released, so the game
+// logic must not release them itself.
 func (scene *Scene) DestroyGameObject(name string) error {
 	gmob := scene.FindGameObject(name)
 
@@ -479,30 +1050,165 @@ func (scene *Scene) DestroyGameObject(name string) error {
 		return RaiseErrorNoGameObjectOnScene(scene, name)
 	}
 
+	return destroyGameObject(gmob)
+}
+
+// destroyGameObject deactivates all the game
+// object components, stops drawing it and sets
+// it for removal from its scene. The descendants
+// of the game object are destroyed first. The sprite
+// is removed from its canvas, and the resources the
+// engine loaded for the game object are released.
+func destroyGameObject(gmob *GameObject) error {
+	if gmob.destroyed {
+		return nil
+	}
+
+	for _, child := range gmob.Children() {
+		err := destroyGameObject(child)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	// Deactivate all the game object components.
-	for _, component := range gmob.components {
-		err := component.Destroy()
+	for _, entry := range gmob.componentOrder {
+		err := gmob.disableComponent(entry)
+
+		if err != nil {
+			return err
+		}
+
+		err = entry.comp.Destroy()
 
 		if err != nil {
 			return err
 		}
 
-		component.SetActive(false)
+		entry.comp.SetActive(false)
+	}
+
+	if persistent, ok := noDestroyOnSceneSwitch[gmob.name]; ok && persistent == gmob {
+		delete(noDestroyOnSceneSwitch, gmob.name)
+	}
+
+	if scene := gmob.scene; scene != nil {
+		scene.events.unsubscribeGameObject(gmob)
+
+		if scene.gmobNameIndex[gmob.name] == gmob {
+			scene.destructionBuffer = append(scene.destructionBuffer, gmob)
+		}
+	}
+
+	err := gmob.Transform().SetParent(nil)
+
+	if err != nil {
+		return err
 	}
 
-	gmob.Transform().SetParent(nil)
+	gmob.transform.SetOwner(nil)
+	releaseHandle(gmob.handle)
+	gmob.handle = Handle{}
 	gmob.SetScene(nil)
 	gmob.SetDraw(false)
 	gmob.destroyed = true
 
-	scene.destructionBuffer[name] = struct{}{}
+	// The sprite is removed before its
+	// texture can be evicted.
+	if gmob.sprite != nil {
+		detachSprite(gmob.sprite)
+	}
+
+	return gmob.releaseResources()
+}
+
+// liveGameObjects returns all the game objects placed
+// on the scene and not destroyed in the Z update order
+// followed by the ones set to be added to the scene.
+func (scene *Scene) liveGameObjects() ([]*GameObject, error) {
+	gmobs := []*GameObject{}
+	err := scene.gmobs.VisitInOrder(func(key ZUpdateKey, gmob *GameObject) error {
+		if !gmob.destroyed {
+			gmobs = append(gmobs, gmob)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, buffered := range scene.addBuffer {
+		gmobs = append(gmobs, buffered.gmob)
+	}
+
+	return gmobs, nil
+}
+
+// persistentGameObjects returns all the game objects
+// of the scene to be carried over to the other scene
+// when the scene is unloaded.
+func (scene *Scene) persistentGameObjects() ([]*GameObject, error) {
+	gmobs, err := scene.liveGameObjects()
+
+	if err != nil {
+		return nil, err
+	}
+
+	persistent := []*GameObject{}
+
+	for _, gmob := range gmobs {
+		if persistsOnSceneSwitch(gmob) {
+			persistent = append(persistent, gmob)
+		}
+	}
+
+	return persistent, nil
+}
+
+// checkNamesFree returns an error if the scene has
+// or is set to add a game object with the name of
+// any of the game objects.
+func (scene *Scene) checkNamesFree(gmobs []*GameObject) error {
+	names := make(map[string]struct{}, len(gmobs))
+
+	for _, gmob := range gmobs {
+		if scene.HasGameObject(gmob.name) {
+			return fmt.Errorf("scene '%s' already has game object '%s'",
+				scene.name, gmob.name)
+		}
+
+		names[gmob.name] = struct{}{}
+	}
+
+	for _, buffered := range scene.addBuffer {
+		if _, ok := names[buffered.gmob.name]; ok {
+			return fmt.Errorf("game object '%s' is already set to be added to scene '%s'",
+				buffered.gmob.name, scene.name)
+		}
+	}
 
 	return nil
 }
 
+// persistsOnSceneSwitch returns true if the game
+// object or any of its ancestors is set to be not
+// destroyed on scene switch.
+func persistsOnSceneSwitch(gmob *GameObject) bool {
+	for ; gmob != nil; gmob = gmob.Parent() {
+		if other, ok := noDestroyOnSceneSwitch[gmob.name]; ok && other == gmob {
+			return true
+		}
+	}
+
+	return false
+}
+
 // DontDestroyOnSceneSwitch sets the game object
 // under the specified name to be not destroyed on
-// scene switch.
+// scene switch along with all its descendants.
 func (scene *Scene) DontDestroyOnSceneSwitch(gmobName string) error {
 	gmob := scene.FindGameObject(gmobName)
 
@@ -521,14 +1227,68 @@ func (scene *Scene) DontDestroyOnSceneSwitch(gmobName string) error {
 
 // SwitchTo starts playing a different scene
 // under the specified name.
-func (scene *Scene) SwitchTo(sceneName string) error {
+//
+// All the additive scenes are stopped and the
+// current scene is unloaded: all its game objects
+// except the ones set to be not destroyed on scene switch
+// and their descendants are destroyed, its tasks are stopped, its systems
+// are destroyed and its resource files are closed.
+// The persistent game objects are moved to the
+// other scene along with their event subscriptions.
+func (scene *Scene) SwitchTo(sceneName string, options ...SceneTransitionOption) error {
 	otherScene, ok := scenes[sceneName]
 
 	if !ok {
 		return NewErrorSceneDoesntExist(sceneName)
 	}
 
-	for _, gmob := range noDestroyOnSceneSwitch {
+	if otherScene == scene {
+		return fmt.Errorf("scene '%s' cannot be switched to itself", sceneName)
+	}
+
+	if scene.additive {
+		return fmt.Errorf("additive scene '%s' cannot be switched, stop it instead",
+			scene.name)
+	}
+
+	if otherScene.additive {
+		return fmt.Errorf("scene '%s' is already played additively", sceneName)
+	}
+
+	var params sceneTransitionParameters
+
+	for i := 0; i < len(options); i++ {
+		option := options[i]
+		err := option(&params)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if params.beforeUnload != nil {
+		err := params.beforeUnload(scene, otherScene)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// The persistent game objects of the additive
+	// scenes are moved to the current scene first.
+	err := stopAdditiveScenes()
+
+	if err != nil {
+		return err
+	}
+
+	persistent, subs, err := scene.unload()
+
+	if err != nil {
+		return err
+	}
+
+	for _, gmob := range persistent {
 		err := otherScene.AddGameObject(
 			gmob, gmob.zUpdate)
 
@@ -537,34 +1297,180 @@ func (scene *Scene) SwitchTo(sceneName string) error {
 		}
 	}
 
-	err := otherScene.Start()
+	for _, sub := range subs {
+		otherScene.events.subscribe(sub)
+	}
+
+	err = otherScene.Start()
 
 	if err != nil {
 		return err
 	}
 
+	if params.afterStart != nil {
+		err := params.afterStart(scene, otherScene)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// unload destroys all the game objects of the scene
+// except the persistent ones, stops all the tasks,
+// destroys and removes all the systems and
+// closes all the resource files. The scene is left
+// empty and can be filled and started again.
+//
+// The persistent game objects removed from the scene
+// are returned in the Z update order along with their
+// event subscriptions to be moved to the other scene.
+func (scene *Scene) unload() ([]*GameObject, []*Subscription, error) {
+	gmobs, err := scene.liveGameObjects()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The buffered game objects are placed
+	// on the other scene at the requested Z.
+	for _, buffered := range scene.addBuffer {
+		buffered.gmob.zUpdate = buffered.zUpd
+	}
+
+	persistent := []*GameObject{}
+	destroyed := []*GameObject{}
+
+	for _, gmob := range gmobs {
+		if persistsOnSceneSwitch(gmob) {
+			persistent = append(persistent, gmob)
+		} else {
+			destroyed = append(destroyed, gmob)
+		}
+	}
+
+	// The persistent game objects are carried over
+	// along with all their descendants, and the ones
+	// whose parents are destroyed are detached.
+	for _, gmob := range persistent {
+		if parent := gmob.Parent(); parent != nil && !persistsOnSceneSwitch(parent) {
+			err := gmob.SetParent(nil)
+
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	for _, gmob := range destroyed {
+		err := destroyGameObject(gmob)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, gmob := range persistent {
+		gmob.SetScene(nil)
+	}
+
+	subs := []*Subscription{}
+
+	for _, gmob := range persistent {
+		subs = append(subs, scene.events.takeGameObject(gmob)...)
+	}
+
+	err = scene.taskMgr.Destroy()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = scene.destroySystems()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for loaderID, loader := range scene.resourceLoaders {
+		err := loader.Close()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		delete(scene.resourceLoaders, loaderID)
+	}
+
+	// The Z update index may be being visited
+	// right now, so it's replaced with a new one
+	// instead of being cleared.
+	gmobsIndex, err := scene.gmobsProducer.Produce()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scene.staleGmobs = append(scene.staleGmobs, scene.gmobs)
+	scene.gmobs = gmobsIndex
+	scene.gmobNameIndex = map[string]*GameObject{}
+	scene.componentIndex = map[string]map[*GameObject]struct{}{}
+	scene.tagIndex = map[string]map[*GameObject]struct{}{}
+	scene.events = newEventBus()
+	scene.addBuffer = []bufferedGameObject{}
+	scene.addBufferIndex = map[*GameObject]int{}
+	scene.destructionBuffer = []*GameObject{}
+	scene.changeZBuffer = []changeZ{}
+	scene.moveBuffer = []moveGameObject{}
+	scene.fixedAccumulator = 0
+
+	if scene.visiting <= 0 {
+		err = scene.disposeStaleGameObjects()
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return persistent, subs, nil
+}
+
 // NewScene creates a new scene to
 // place game objects onto.
-func NewScene(name string, gmobsDictProducer collections.UnrestrictedSortedDictionaryProducer[cmath.Fixed, map[*GameObject]struct{}]) (*Scene, error) {
+//
+// The producer creates the dictionaries of the game
+// objects indexed by their ZUpdateKey, which keeps the
+// ones with the same Z update coordinate in the order
+// of their insertion into the scene.
+func NewScene(name string, gmobsDictProducer collections.UnrestrictedSortedDictionaryProducer[ZUpdateKey, *GameObject]) (*Scene, error) {
 	gmobs, err := gmobsDictProducer.Produce()
 
 	if err != nil {
 		return nil, err
 	}
 
+	drawLayout := render.NewLayout()
+
 	return &Scene{
 		name:              name,
-		addBuffer:         map[*GameObject]float32{},
+		addBuffer:         []bufferedGameObject{},
+		addBufferIndex:    map[*GameObject]int{},
 		changeZBuffer:     []changeZ{},
 		gmobs:             gmobs,
-		destructionBuffer: map[string]struct{}{},
+		gmobsProducer:     gmobsDictProducer,
+		destructionBuffer: []*GameObject{},
 		gmobNameIndex:     map[string]*GameObject{},
-		systems:           map[string]System{},
+		componentIndex:    map[string]map[*GameObject]struct{}{},
+		tagIndex:          map[string]map[*GameObject]struct{}{},
+		systems:           map[string]*systemEntry{},
+		events:            newEventBus(),
 		taskMgr:           tasking.NewTaskManager(),
-		layout:            render.NewLayout(),
+		layout:            drawLayout,
+		ownLayout:         drawLayout,
+		moveBuffer:        []moveGameObject{},
 		resourceLoaders:   map[string]*resources.ResourceLoader{},
+		fixedTimestep:     defaultFixedTimestep,
 	}, nil
 }
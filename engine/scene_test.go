@@ -0,0 +1,707 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/math/geometry"
+	"github.com/alacrity-engine/core/render"
+	"github.com/alacrity-engine/core/system/collections"
+	"github.com/zergon321/mempool"
+)
+
+const testCounterTypeID = "engine__Counter"
+
+// counter is a test component that
+// counts its updates and references
+// another game object.
+type counter struct {
+	BaseComponent
+	Value  int
+	Target *GameObject
+}
+
+func (c *counter) TypeID() string {
+	return testCounterTypeID
+}
+
+func (c *counter) Update() error {
+	c.Value++
+	return nil
+}
+
+func init() {
+	compTypeRegistry = map[string]ComponentTypeEntry{
+		testCounterTypeID: {
+			Name:        "Counter",
+			PkgPath:     "github.com/alacrity-engine/core/engine",
+			Constructor: func() Component { return &counter{} },
+			Fields: map[string]ComponentTypeFieldEntry{
+				"Value": {
+					Name:   "Value",
+					Type:   "int",
+					Getter: func(comp Component) interface{} { return comp.(*counter).Value },
+					Setter: func(comp Component, value interface{}) { comp.(*counter).Value = value.(int) },
+				},
+				"Target": {
+					Name:   "Target",
+					Type:   "*engine.GameObject",
+					Getter: func(comp Component) interface{} { return comp.(*counter).Target },
+					Setter: func(comp Component, value interface{}) { comp.(*counter).Target = value.(*GameObject) },
+				},
+			},
+		},
+	}
+}
+
+func newTestGameObjectsProducer(t *testing.T) collections.UnrestrictedSortedDictionaryProducer[ZUpdateKey, *GameObject] {
+	t.Helper()
+
+	nodePool, err := mempool.NewPool[*collections.UnrestrictedAVLNode[ZUpdateKey, *GameObject]](
+		func() *collections.UnrestrictedAVLNode[ZUpdateKey, *GameObject] {
+			return new(collections.UnrestrictedAVLNode[ZUpdateKey, *GameObject])
+		})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treePool, err := mempool.NewPool[*collections.AVLUnrestrictedSortedDictionary[ZUpdateKey, *GameObject]](
+		func() *collections.AVLUnrestrictedSortedDictionary[ZUpdateKey, *GameObject] {
+			tree, _ := collections.NewAVLUnrestrictedSortedDictionary[ZUpdateKey, *GameObject]()
+			return tree
+		})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return collections.NewAVLUnrestrictedSortedDictionaryProducer(treePool, nodePool)
+}
+
+func newTestScene(t *testing.T, name string) *Scene {
+	t.Helper()
+
+	scene, err := NewScene(name, newTestGameObjectsProducer(t))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return scene
+}
+
+func counterDefinition(name string, value int, target string) *definitions.GameObjectDefinition {
+	data := map[string]interface{}{
+		"Value": value,
+	}
+
+	if target != "" {
+		data["Target"] = definitions.GameObjectPointer{Name: target}
+	}
+
+	return &definitions.GameObjectDefinition{
+		Name:   name,
+		Active: true,
+		Components: []*definitions.ComponentDefinition{{
+			TypeName: testCounterTypeID,
+			Active:   true,
+			Data:     data,
+		}},
+	}
+}
+
+func TestInstantiatePrefabResolvesForwardPointers(t *testing.T) {
+	scene := newTestScene(t, "prefab")
+	prefab := &definitions.Prefab{
+		Name: "pair",
+		TransformRoot: &definitions.TransformDefinition{
+			Position: geometry.V(10, 20),
+			Gmob:     counterDefinition("first", 1, "second"),
+			Children: []*definitions.TransformDefinition{{
+				Position: geometry.V(30, 40),
+				Gmob:     counterDefinition("second", 2, "first"),
+			}},
+		},
+	}
+
+	root, err := InstantiatePrefab(scene, prefab, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := scene.FindGameObject("second")
+
+	if second == nil {
+		t.Fatal("the child game object is not on the scene")
+	}
+
+	if root.FindComponent(testCounterTypeID).(*counter).Target != second {
+		t.Fatal("the forward pointer is not resolved")
+	}
+
+	if second.FindComponent(testCounterTypeID).(*counter).Target != root {
+		t.Fatal("the backward pointer is not resolved")
+	}
+
+	if second.Transform().Parent() != root.Transform() {
+		t.Fatal("the child transform is not attached to the root")
+	}
+}
+
+func TestInstantiatePrefabUnresolvedPointer(t *testing.T) {
+	scene := newTestScene(t, "prefab")
+	prefab := &definitions.Prefab{
+		Name: "lonely",
+		TransformRoot: &definitions.TransformDefinition{
+			Gmob: counterDefinition("lonely", 0, "nobody"),
+		},
+	}
+
+	_, err := InstantiatePrefab(scene, prefab, nil)
+
+	if unresolved, ok := err.(*ErrorUnresolvedPointer); !ok {
+		t.Fatalf("expected an unresolved pointer error, got %v", err)
+	} else if unresolved.FieldName() != "Target" {
+		t.Fatalf("wrong field name: %s", unresolved.FieldName())
+	}
+}
+
+func TestInstantiatePrefabFailureLeavesNothing(t *testing.T) {
+	scene := newTestScene(t, "prefab")
+	parent := geometry.NewTransform(nil)
+	existing := NewGameObject(nil, "second", nil)
+	err := scene.AddGameObject(existing, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefabs := []*definitions.Prefab{{
+		Name: "unresolved",
+		TransformRoot: &definitions.TransformDefinition{
+			Gmob: counterDefinition("first", 0, "nobody"),
+		},
+	}, {
+		Name: "clashing",
+		TransformRoot: &definitions.TransformDefinition{
+			Gmob: counterDefinition("first", 0, ""),
+			Children: []*definitions.TransformDefinition{{
+				Gmob: counterDefinition("second", 0, ""),
+			}},
+		},
+	}}
+
+	for _, prefab := range prefabs {
+		_, err := InstantiatePrefab(scene, prefab, parent)
+
+		if err == nil {
+			t.Fatalf("no error for prefab '%s'", prefab.Name)
+		}
+
+		if len(parent.Children()) != 0 {
+			t.Fatalf("prefab '%s' is left attached to the parent", prefab.Name)
+		}
+
+		if len(scene.addBuffer) != 0 {
+			t.Fatalf("prefab '%s' is left in the add buffer", prefab.Name)
+		}
+	}
+}
+
+func TestInstantiatePrefabDuplicateNames(t *testing.T) {
+	scene := newTestScene(t, "prefab")
+	prefab := &definitions.Prefab{
+		Name: "duplicate",
+		TransformRoot: &definitions.TransformDefinition{
+			Gmob: counterDefinition("first", 0, "second"),
+			Children: []*definitions.TransformDefinition{{
+				Gmob: counterDefinition("second", 0, ""),
+			}, {
+				Gmob: counterDefinition("second", 0, ""),
+			}},
+		},
+	}
+
+	// The generated names don't clash, but
+	// the pointer can't tell the game objects apart.
+	_, err := InstantiatePrefab(scene, prefab, nil,
+		InstantiationOptionWithAutoNames())
+
+	if err == nil {
+		t.Fatal("no error for the duplicate game object names")
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	scene := newTestScene(t, "original")
+	prefab := &definitions.Prefab{
+		Name: "pair",
+		TransformRoot: &definitions.TransformDefinition{
+			Position: geometry.V(10, 20),
+			Gmob:     counterDefinition("first", 5, "second"),
+			Children: []*definitions.TransformDefinition{{
+				Position: geometry.V(30, 40),
+				Gmob:     counterDefinition("second", 7, ""),
+			}},
+		},
+	}
+
+	_, err := InstantiatePrefab(scene, prefab, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.FindGameObject("second").SetActive(false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sceneDef, err := scene.Snapshot()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(sceneDef)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded definitions.SceneDefinition
+	err = gob.NewDecoder(&buf).Decode(&decoded)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := newTestScene(t, "restored")
+	err = InstantiateScene(restored, &decoded)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = restored.addBufferedGameObjects()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := restored.FindGameObject("first")
+	second := restored.FindGameObject("second")
+
+	if first == nil || second == nil {
+		t.Fatal("the game objects are not restored")
+	}
+
+	firstCounter := first.FindComponent(testCounterTypeID).(*counter)
+
+	// The value was incremented by the update.
+	if firstCounter.Value != 6 {
+		t.Fatalf("wrong value: %d", firstCounter.Value)
+	}
+
+	if firstCounter.Target != second {
+		t.Fatal("the pointer is not restored")
+	}
+
+	if second.Transform().Parent() != first.Transform() {
+		t.Fatal("the hierarchy is not restored")
+	}
+
+	if second.Transform().Position() != geometry.V(30, 40) {
+		t.Fatalf("wrong position: %v", second.Transform().Position())
+	}
+
+	if !first.Active() || second.Active() {
+		t.Fatal("the activity is not restored")
+	}
+}
+
+// testSprite is a sprite state with no
+// graphics resources behind it.
+type testSprite struct {
+	texture         *render.Texture
+	program         *render.ShaderProgram
+	colorMask       render.ColorMask
+	targetArea      geometry.Rect
+	vertexDrawMode  render.DrawMode
+	textureDrawMode render.DrawMode
+	colorDrawMode   render.DrawMode
+}
+
+func (sprite *testSprite) Texture() *render.Texture             { return sprite.texture }
+func (sprite *testSprite) ShaderProgram() *render.ShaderProgram { return sprite.program }
+func (sprite *testSprite) ColorMask() render.ColorMask          { return sprite.colorMask }
+func (sprite *testSprite) TargetArea() geometry.Rect            { return sprite.targetArea }
+func (sprite *testSprite) VertexDrawMode() render.DrawMode      { return sprite.vertexDrawMode }
+func (sprite *testSprite) DrawMode() render.DrawMode            { return sprite.textureDrawMode }
+func (sprite *testSprite) ColorDrawMode() render.DrawMode       { return sprite.colorDrawMode }
+func (sprite *testSprite) Canvas() *render.Canvas               { return nil }
+func (sprite *testSprite) Batch() *render.Batch                 { return nil }
+
+// testResources is a resource index
+// of the resources with known IDs.
+type testResources struct {
+	ids    map[interface{}][2]string
+	frames map[geometry.Rect][2]string
+}
+
+func (index testResources) FindResourceID(resource interface{}) (string, string, bool) {
+	id, ok := index.ids[resource]
+	return id[0], id[1], ok
+}
+
+func (index testResources) FindSpritesheetFrame(texture *render.Texture, area geometry.Rect) (string, string, bool) {
+	frame, ok := index.frames[area]
+	return frame[0], frame[1], ok
+}
+
+func TestSnapshotSpriteRoundTrip(t *testing.T) {
+	texture := &render.Texture{}
+	program := &render.ShaderProgram{}
+	snapshot := &sceneSnapshot{
+		resources: testResources{
+			ids: map[interface{}][2]string{
+				texture: {definitions.ResourceTypeTexture, "player"},
+				program: {definitions.ResourceTypeShaderProgram, "outline"},
+			},
+			frames: map[geometry.Rect][2]string{
+				geometry.R(16, 0, 32, 16): {"player", "player_idle_1"},
+			},
+		},
+	}
+
+	// The canvas and the batch can't be created
+	// without the graphics context, so the sprites
+	// are not placed onto any.
+	defs := []*definitions.SpriteDefinition{{
+		ColorMask:       render.RGBARepeat4(render.RGB(1, 0.5, 0.25)),
+		TargetArea:      geometry.R(0, 0, 64, 64),
+		VertexDrawMode:  render.DrawModeDynamic,
+		TextureDrawMode: render.DrawModeStream,
+		ColorDrawMode:   render.DrawModeStatic,
+		ShaderProgramID: "outline",
+		TextureID:       "player",
+	}, {
+		ColorMask:       render.RGBAFullOpaque(),
+		TargetArea:      geometry.R(16, 0, 32, 16),
+		VertexDrawMode:  render.DrawModeStatic,
+		TextureDrawMode: render.DrawModeDynamic,
+		ColorDrawMode:   render.DrawModeStream,
+		ShaderProgramID: "outline",
+		TextureID:       "player",
+		SpritesheetID:   "player",
+		FrameName:       "player_idle_1",
+	}}
+
+	for _, def := range defs {
+		sprite := &testSprite{
+			texture:         texture,
+			program:         program,
+			colorMask:       def.ColorMask,
+			targetArea:      def.TargetArea,
+			vertexDrawMode:  def.VertexDrawMode,
+			textureDrawMode: def.TextureDrawMode,
+			colorDrawMode:   def.ColorDrawMode,
+		}
+
+		spriteDef, err := snapshot.sprite(sprite)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		err = gob.NewEncoder(&buf).Encode(spriteDef)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded definitions.SpriteDefinition
+		err = gob.NewDecoder(&buf).Decode(&decoded)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(&decoded, def) {
+			t.Fatalf("wrong sprite definition: %+v, expected %+v", decoded, *def)
+		}
+	}
+}
+
+// switcher is a test component that switches
+// the scene on its first update.
+type switcher struct {
+	BaseComponent
+	target string
+	events *[]string
+}
+
+func (s *switcher) TypeID() string {
+	return "engine__Switcher"
+}
+
+func (s *switcher) Update() error {
+	if s.target == "" {
+		return nil
+	}
+
+	target := s.target
+	s.target = ""
+
+	return s.GameObject().Scene().SwitchTo(target,
+		SceneTransitionOptionWithBeforeUnload(func(from, to *Scene) error {
+			*s.events = append(*s.events, "unload "+from.Name())
+			return nil
+		}),
+		SceneTransitionOptionWithAfterStart(func(from, to *Scene) error {
+			*s.events = append(*s.events, "start "+to.Name())
+			return nil
+		}))
+}
+
+func (s *switcher) Destroy() error {
+	*s.events = append(*s.events, "destroy "+s.GameObject().Name())
+	return nil
+}
+
+func TestSwitchToUnloadsScene(t *testing.T) {
+	events := []string{}
+	first := newTestScene(t, "first")
+	second := newTestScene(t, "second")
+
+	for _, scene := range []*Scene{first, second} {
+		err := AddScene(scene)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer RemoveScene(scene.name)
+	}
+
+	t.Cleanup(func() {
+		currentSceneName = ""
+		delete(noDestroyOnSceneSwitch, "player")
+	})
+
+	for _, name := range []string{"trigger", "player", "enemy"} {
+		gmob := NewGameObject(nil, name, nil)
+		comp := &switcher{events: &events}
+		comp.SetActive(true)
+
+		if name == "trigger" {
+			comp.target = second.name
+		}
+
+		err := gmob.AddComponent(comp, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = first.AddGameObject(gmob, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := first.DontDestroyOnSceneSwitch("player")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hits := 0
+	_, err = SubscribeGameObject(first, first.FindGameObject("player"),
+		func(event hitEvent) error {
+			hits++
+			return nil
+		})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	taskCalls := 0
+	err = first.TaskManager().StartTask("task", func() (bool, error) {
+		taskCalls++
+		return true, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = first.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The scene is switched while
+	// its game objects are updated.
+	err = first.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"unload first",
+		"destroy trigger",
+		"destroy enemy",
+		"start second",
+	}
+
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("wrong transition: %v", events)
+	}
+
+	if CurrentScene() != second {
+		t.Fatal("the other scene is not current")
+	}
+
+	if taskCalls != 0 {
+		t.Fatal("the task of the unloaded scene is performed")
+	}
+
+	if first.HasGameObject("player") || first.HasGameObject("enemy") {
+		t.Fatal("the unloaded scene still has game objects")
+	}
+
+	player := second.FindGameObject("player")
+
+	if player == nil || player.Scene() != second {
+		t.Fatal("the persistent game object is not moved")
+	}
+
+	// The persistent game object keeps
+	// its event subscriptions.
+	err = Publish(second, hitEvent{damage: 1})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hits != 1 || len(first.events.subscribers) != 0 {
+		t.Fatal("the event subscription is not moved")
+	}
+
+	err = second.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSwitchToCarriesPersistentSubtrees(t *testing.T) {
+	first := newTestScene(t, "first")
+	second := newTestScene(t, "second")
+
+	for _, scene := range []*Scene{first, second} {
+		err := AddScene(scene)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer RemoveScene(scene.name)
+	}
+
+	t.Cleanup(func() {
+		currentSceneName = ""
+		delete(noDestroyOnSceneSwitch, "player")
+		delete(noDestroyOnSceneSwitch, "marker")
+	})
+
+	gmobs := map[string]*GameObject{}
+
+	for _, name := range []string{"player", "weapon", "level", "marker"} {
+		gmob := NewGameObject(nil, name, nil)
+		err := first.AddGameObject(gmob, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gmobs[name] = gmob
+	}
+
+	for child, parent := range map[string]string{
+		"weapon": "player",
+		"marker": "level",
+	} {
+		err := gmobs[child].SetParent(gmobs[parent])
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"player", "marker"} {
+		err := first.DontDestroyOnSceneSwitch(name)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := first.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = first.SwitchTo(second.name)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The descendant of the persistent game object
+	// is carried over, and the persistent child of
+	// the destroyed game object is detached.
+	if !gmobs["level"].Destroyed() || second.HasGameObject("level") {
+		t.Fatal("the game object is not destroyed on scene switch")
+	}
+
+	for _, name := range []string{"player", "weapon", "marker"} {
+		if gmobs[name].Destroyed() || second.FindGameObject(name) != gmobs[name] {
+			t.Fatalf("game object '%s' is not carried over", name)
+		}
+	}
+
+	if gmobs["weapon"].Parent() != gmobs["player"] {
+		t.Fatal("the descendant is detached from the persistent game object")
+	}
+
+	if gmobs["marker"].Parent() != nil {
+		t.Fatal("the persistent game object is not detached from the destroyed parent")
+	}
+}
@@ -0,0 +1,399 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/alacrity-engine/core/anim"
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/math/geometry"
+	"github.com/alacrity-engine/core/render"
+	"github.com/alacrity-engine/core/resources"
+	"github.com/golang/freetype/truetype"
+)
+
+type (
+	// sceneSnapshot holds the data required
+	// to build the definition of the scene.
+	sceneSnapshot struct {
+		scene          *Scene
+		resources      resourceIndex
+		transformIndex map[*geometry.Transform]*GameObject
+		zUpdates       map[*GameObject]float32
+	}
+
+	// resourceIndex looks up the IDs
+	// of the loaded resources.
+	resourceIndex interface {
+		FindResourceID(resource interface{}) (string, string, bool)
+		FindSpritesheetFrame(texture *render.Texture, area geometry.Rect) (string, string, bool)
+	}
+
+	// sceneResources looks up the resources
+	// in all the scene resource loaders.
+	sceneResources map[string]*resources.ResourceLoader
+
+	// spriteState is the state of
+	// the sprite stored in its definition.
+	spriteState interface {
+		Texture() *render.Texture
+		ShaderProgram() *render.ShaderProgram
+		ColorMask() render.ColorMask
+		TargetArea() geometry.Rect
+		VertexDrawMode() render.DrawMode
+		DrawMode() render.DrawMode
+		ColorDrawMode() render.DrawMode
+		Canvas() *render.Canvas
+		Batch() *render.Batch
+	}
+)
+
+// Snapshot creates the definition of the scene out
+// of all its game objects, including the ones set
+// to be added, so the definition can be encoded
+// and then instantiated again with InstantiateScene.
+//
+// Field values of the components are obtained with
+// the registered getters. Game objects, components
+// and batches are replaced with pointers. Textures,
+// pictures, fonts and animations are replaced with
+// resource pointers if they were loaded by one of
+// the scene resource loaders.
+func (scene *Scene) Snapshot() (*definitions.SceneDefinition, error) {
+	snapshot := &sceneSnapshot{
+		scene:          scene,
+		resources:      sceneResources(scene.resourceLoaders),
+		transformIndex: map[*geometry.Transform]*GameObject{},
+		zUpdates:       map[*GameObject]float32{},
+	}
+	gmobs := []*GameObject{}
+
+	err := scene.gmobs.VisitInOrder(func(key ZUpdateKey, gmob *GameObject) error {
+		if !gmob.destroyed {
+			gmobs = append(gmobs, gmob)
+			snapshot.zUpdates[gmob] = gmob.zUpdate
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, buffered := range scene.addBuffer {
+		gmobs = append(gmobs, buffered.gmob)
+		snapshot.zUpdates[buffered.gmob] = buffered.zUpd
+	}
+
+	// Find the roots of all the transform
+	// hierarchies the game objects belong to.
+	roots := []*geometry.Transform{}
+	visited := map[*geometry.Transform]struct{}{}
+
+	for _, gmob := range gmobs {
+		snapshot.transformIndex[gmob.transform] = gmob
+	}
+
+	for _, gmob := range gmobs {
+		root := gmob.transform
+
+		for root.Parent() != nil {
+			root = root.Parent()
+		}
+
+		if _, ok := visited[root]; ok {
+			continue
+		}
+
+		visited[root] = struct{}{}
+		roots = append(roots, root)
+	}
+
+	sceneDef := &definitions.SceneDefinition{
+		Name:       scene.name,
+		Transforms: make([]*definitions.TransformDefinition, 0, len(roots)),
+	}
+
+	for _, root := range roots {
+		transformDef, err := snapshot.transform(root)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sceneDef.Transforms = append(sceneDef.Transforms, transformDef)
+	}
+
+	return sceneDef, nil
+}
+
+// transform creates the definition of the transform
+// and all its children.
+func (snapshot *sceneSnapshot) transform(transform *geometry.Transform) (*definitions.TransformDefinition, error) {
+	def := &definitions.TransformDefinition{
+		Position: transform.Position(),
+		Angle:    transform.Angle(),
+		Scale:    transform.Scale(),
+		Children: []*definitions.TransformDefinition{},
+	}
+
+	if gmob, ok := snapshot.transformIndex[transform]; ok {
+		gmobDef, err := snapshot.gameObject(gmob)
+
+		if err != nil {
+			return nil, err
+		}
+
+		def.Gmob = gmobDef
+	}
+
+	for _, child := range transform.Children() {
+		childDef, err := snapshot.transform(child)
+
+		if err != nil {
+			return nil, err
+		}
+
+		def.Children = append(def.Children, childDef)
+	}
+
+	return def, nil
+}
+
+// gameObject creates the definition of the game
+// object with all its components and the sprite.
+// The game objects not started yet are stored as
+// drawn unless their draw flag was set, because
+// the start sets them to be drawn.
+func (snapshot *sceneSnapshot) gameObject(gmob *GameObject) (*definitions.GameObjectDefinition, error) {
+	def := &definitions.GameObjectDefinition{
+		Name:       gmob.name,
+		ZUpdate:    float64(snapshot.zUpdates[gmob]),
+		Components: make([]*definitions.ComponentDefinition, 0, len(gmob.componentOrder)),
+		Draw:       gmob.draw || !gmob.drawSet,
+		Active:     gmob.active,
+		Tags:       gmob.Tags(),
+		Layer:      uint32(gmob.layer),
+	}
+
+	// Components are stored in the order they were
+	// added so the indices of the component pointers
+	// stay valid after the instantiation.
+	for _, entry := range gmob.additionOrder {
+		compDef, err := snapshot.component(entry.comp)
+
+		if err != nil {
+			return nil, err
+		}
+
+		compDef.Priority = entry.priority
+		def.Components = append(def.Components, compDef)
+	}
+
+	if gmob.sprite != nil {
+		spriteDef, err := snapshot.sprite(gmob.sprite)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot snapshot the sprite of game object '%s': %w",
+				gmob.name, err)
+		}
+
+		def.Sprite = spriteDef
+	}
+
+	return def, nil
+}
+
+// component creates the definition of the component
+// out of the values returned by the registered getters.
+func (snapshot *sceneSnapshot) component(comp Component) (*definitions.ComponentDefinition, error) {
+	regComp, ok := comp.(RegisteredComponent)
+
+	if !ok {
+		return nil, fmt.Errorf("the component can't be registered")
+	}
+
+	typeID := regComp.TypeID()
+	entry, ok := compTypeRegistry[typeID]
+
+	if !ok {
+		return nil, NewErrorComponentTypeNotRegistered(typeID)
+	}
+
+	def := &definitions.ComponentDefinition{
+		TypeName: typeID,
+		Active:   comp.Active(),
+		Data:     map[string]interface{}{},
+	}
+
+	for fieldName, field := range entry.Fields {
+		if field.Getter == nil {
+			continue
+		}
+
+		value, ok, err := snapshot.value(field.Getter(comp))
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot snapshot field '%s' of component '%s': %w",
+				fieldName, typeID, err)
+		}
+
+		if ok {
+			def.Data[fieldName] = value
+		}
+	}
+
+	return def, nil
+}
+
+// value replaces references to game objects, components,
+// batches and resources with pointers. If the reference
+// is nil, the value is not stored at all.
+func (snapshot *sceneSnapshot) value(value interface{}) (interface{}, bool, error) {
+	if isNilReference(value) {
+		return nil, false, nil
+	}
+
+	switch val := value.(type) {
+	case *GameObject:
+		return definitions.GameObjectPointer{
+			Name: val.name,
+		}, true, nil
+
+	case *render.Batch:
+		canvas := val.Canvas()
+
+		if canvas == nil {
+			return nil, false, fmt.Errorf(
+				"batch '%s' doesn't belong to any canvas", val.Name())
+		}
+
+		return definitions.BatchPointer{
+			CanvasID: canvas.Name(),
+			BatchID:  val.Name(),
+		}, true, nil
+
+	case RegisteredComponent:
+		gmob := val.GameObject()
+
+		if gmob == nil {
+			return nil, false, fmt.Errorf(
+				"component '%s' is not attached to any game object", val.TypeID())
+		}
+
+		index := 0
+
+		for i, comp := range gmob.FindComponents(val.TypeID()) {
+			if comp == val {
+				index = i
+				break
+			}
+		}
+
+		return definitions.ComponentPointer{
+			GmobName: gmob.name,
+			CompType: val.TypeID(),
+			Index:    index,
+		}, true, nil
+
+	case *render.Texture, *render.Picture, *truetype.Font, *anim.Animation:
+		resourceType, resourceID, found := snapshot.resources.FindResourceID(val)
+
+		if !found {
+			return nil, false, NewErrorResourceNotLoaded(val)
+		}
+
+		return definitions.ResourcePointer{
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+		}, true, nil
+
+	default:
+		return value, true, nil
+	}
+}
+
+// sprite creates the definition of the sprite.
+//
+// If the sprite draws a named frame of a loaded
+// spritesheet, the frame is stored along with
+// the texture and the target area.
+func (snapshot *sceneSnapshot) sprite(sprite spriteState) (*definitions.SpriteDefinition, error) {
+	_, textureID, found := snapshot.resources.FindResourceID(sprite.Texture())
+
+	if !found {
+		return nil, fmt.Errorf("the texture was not loaded by the scene")
+	}
+
+	def := &definitions.SpriteDefinition{
+		ColorMask:       sprite.ColorMask(),
+		TargetArea:      sprite.TargetArea(),
+		VertexDrawMode:  sprite.VertexDrawMode(),
+		TextureDrawMode: sprite.DrawMode(),
+		ColorDrawMode:   sprite.ColorDrawMode(),
+		TextureID:       textureID,
+	}
+
+	def.SpritesheetID, def.FrameName, _ = snapshot.resources.
+		FindSpritesheetFrame(sprite.Texture(), sprite.TargetArea())
+
+	// The shader program passed with
+	// the instantiation options is not
+	// stored in the definition.
+	resourceType, programID, found := snapshot.resources.FindResourceID(sprite.ShaderProgram())
+
+	if found && resourceType == definitions.ResourceTypeShaderProgram {
+		def.ShaderProgramID = programID
+	}
+
+	if canvas := sprite.Canvas(); canvas != nil {
+		def.CanvasID = canvas.Name()
+	}
+
+	if batch := sprite.Batch(); batch != nil {
+		def.BatchID = batch.Name()
+	}
+
+	return def, nil
+}
+
+// FindResourceID looks up the resource
+// in all the scene resource loaders.
+func (loaders sceneResources) FindResourceID(resource interface{}) (string, string, bool) {
+	for _, loader := range loaders {
+		resourceType, resourceID, found := loader.FindResourceID(resource)
+
+		if found {
+			return resourceType, resourceID, true
+		}
+	}
+
+	return "", "", false
+}
+
+// FindSpritesheetFrame looks up the spritesheet
+// frame in all the scene resource loaders.
+func (loaders sceneResources) FindSpritesheetFrame(texture *render.Texture, area geometry.Rect) (string, string, bool) {
+	for _, loader := range loaders {
+		ssID, frameName, found := loader.FindSpritesheetFrame(texture, area)
+
+		if found {
+			return ssID, frameName, true
+		}
+	}
+
+	return "", "", false
+}
+
+// isNilReference returns true if the value
+// is nil or a nil pointer.
+func isNilReference(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	refValue := reflect.ValueOf(value)
+
+	return refValue.Kind() == reflect.Pointer && refValue.IsNil()
+}
@@ -0,0 +1,222 @@
+package engine
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/alacrity-engine/core/definitions"
+)
+
+// StateHash returns the hash of the state of all
+// the game objects of the scene in the Z update order.
+// The names, Z update coordinates, activity, tags,
+// layers and transforms of the game objects are hashed
+// along with the field values of their components
+// obtained with the registered getters.
+//
+// The hash is meant to detect divergence of the
+// same simulation played twice, e.g. during replays
+// or lockstep networking. The game objects, components,
+// batches and resources loaded by the scene are hashed
+// by their names and IDs. The fields holding other
+// pointers, channels or functions, as well as the
+// resources not loaded by the scene, are skipped
+// because their values differ from run to run.
+// The numbers of the generated names are left out
+// as they depend on all the names generated in
+// the process before.
+func (scene *Scene) StateHash() (uint64, error) {
+	h := fnv.New64a()
+	snapshot := &sceneSnapshot{
+		scene:     scene,
+		resources: sceneResources(scene.resourceLoaders),
+	}
+
+	err := scene.gmobs.VisitInOrder(func(key ZUpdateKey, gmob *GameObject) error {
+		if gmob.destroyed {
+			return nil
+		}
+
+		return snapshot.hashGameObject(h, gmob)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return h.Sum64(), nil
+}
+
+// hashGameObject writes the state of
+// the game object to the hash.
+func (snapshot *sceneSnapshot) hashGameObject(h hash.Hash64, gmob *GameObject) error {
+	fmt.Fprintf(h, "%s|%v|%v|%v|%v|%d|", hashedName(gmob.name), gmob.zUpdate,
+		gmob.active, gmob.draw, gmob.Tags(), gmob.layer)
+
+	err := binary.Write(h, binary.LittleEndian, gmob.transform.Data())
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range gmob.additionOrder {
+		err := snapshot.hashComponent(h, entry.comp)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hashComponent writes the activity status and
+// the field values of the component to the hash.
+func (snapshot *sceneSnapshot) hashComponent(h hash.Hash64, comp Component) error {
+	regComp, ok := comp.(RegisteredComponent)
+
+	if !ok {
+		fmt.Fprintf(h, "%T|%v|", comp, comp.Active())
+		return nil
+	}
+
+	typeID := regComp.TypeID()
+	fmt.Fprintf(h, "%s|%v|", typeID, comp.Active())
+	entry, ok := compTypeRegistry[typeID]
+
+	if !ok {
+		return nil
+	}
+
+	fieldNames := make([]string, 0, len(entry.Fields))
+
+	for fieldName, field := range entry.Fields {
+		if field.Getter != nil {
+			fieldNames = append(fieldNames, fieldName)
+		}
+	}
+
+	sort.Strings(fieldNames)
+
+	for _, fieldName := range fieldNames {
+		fieldValue := entry.Fields[fieldName].Getter(comp)
+		value, ok, err := snapshot.value(fieldValue)
+		var notLoaded *ErrorResourceNotLoaded
+
+		switch {
+		case errors.As(err, &notLoaded):
+			// Resources not loaded by the
+			// scene have no stable IDs.
+			continue
+
+		case err != nil:
+			return fmt.Errorf("cannot hash field '%s' of component '%s': %w",
+				fieldName, typeID, err)
+
+		case !ok:
+			value = nil
+
+		case !hashable(reflect.ValueOf(value)):
+			// The pointer addresses
+			// differ from run to run.
+			continue
+		}
+
+		switch ptr := value.(type) {
+		case definitions.GameObjectPointer:
+			ptr.Name = hashedName(ptr.Name)
+			value = ptr
+
+		case definitions.ComponentPointer:
+			ptr.GmobName = hashedName(ptr.GmobName)
+			value = ptr
+		}
+
+		fmt.Fprintf(h, "%s=%v|", fieldName, value)
+	}
+
+	return nil
+}
+
+// hashedName returns the name of the game object
+// without the number if the name is generated by
+// GenerateGameObjectName.
+func hashedName(name string) string {
+	index := strings.LastIndexByte(name, '#')
+
+	if index < 0 || index == len(name)-1 {
+		return name
+	}
+
+	for _, c := range name[index+1:] {
+		if c < '0' || c > '9' {
+			return name
+		}
+	}
+
+	return name[:index+1]
+}
+
+// hashable returns true if the formatted value
+// doesn't depend on the pointer addresses, i.e.
+// the value holds no non-nil pointers, channels
+// or functions.
+func hashable(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer,
+		reflect.Chan, reflect.Func:
+		return value.IsNil()
+
+	case reflect.Interface:
+		return value.IsNil() || hashable(value.Elem())
+
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			if !hashable(value.Index(i)) {
+				return false
+			}
+		}
+
+	case reflect.Map:
+		iter := value.MapRange()
+
+		for iter.Next() {
+			if !hashable(iter.Key()) || !hashable(iter.Value()) {
+				return false
+			}
+		}
+
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if !hashable(value.Field(i)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// hashScenes returns the combined hash
+// of the states of all the scenes.
+func hashScenes(current []*Scene) (uint64, error) {
+	h := fnv.New64a()
+
+	for _, scene := range current {
+		sceneHash, err := scene.StateHash()
+
+		if err != nil {
+			return 0, err
+		}
+
+		fmt.Fprintf(h, "%s|%d|", scene.name, sceneHash)
+	}
+
+	return h.Sum64(), nil
+}
@@ -0,0 +1,371 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alacrity-engine/core/render"
+	"github.com/golang/freetype/truetype"
+)
+
+// spawner is a test component which spawns
+// game objects at the same Z every frame and
+// destroys some of the previously spawned ones.
+type spawner struct {
+	BaseComponent
+	frame    int
+	perFrame int
+	bonus    int
+	log      *[]string
+}
+
+func (s *spawner) TypeID() string {
+	return "engine__Spawner"
+}
+
+func (s *spawner) Update() error {
+	s.frame++
+	scene := s.GameObject().Scene()
+
+	for i := 0; i < s.perFrame; i++ {
+		name := fmt.Sprintf("spawn-%d-%d", s.frame, i)
+		gmob := NewGameObject(nil, name, nil)
+		comp := &counter{Value: i + s.bonus}
+		comp.SetActive(true)
+
+		err := gmob.AddComponent(comp, 0)
+
+		if err != nil {
+			return err
+		}
+
+		err = gmob.AddComponent(newProbe(name, s.log), 1)
+
+		if err != nil {
+			return err
+		}
+
+		err = scene.AddGameObjectInRuntime(gmob, 0)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.frame > 1 {
+		return scene.DestroyGameObject(fmt.Sprintf("spawn-%d-0", s.frame-1))
+	}
+
+	return nil
+}
+
+func runDeterminismTest(t *testing.T, bonus int) ([]uint64, []string) {
+	t.Helper()
+
+	log := []string{}
+	scene := newTestScene(t, "determinism")
+	gmob := NewGameObject(nil, "spawner", nil)
+	comp := &spawner{perFrame: 16, bonus: bonus, log: &log}
+	comp.SetActive(true)
+
+	err := gmob.AddComponent(comp, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.AddGameObject(gmob, -1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = AddScene(scene)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		RemoveScene(scene.name)
+		currentSceneName = ""
+	}()
+
+	err = scene.Start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashes := []uint64{}
+	clock := NewManualClock(time.Unix(0, 0))
+	loop, err := NewLoop(RunConfig{
+		Headless: true,
+		Clock:    clock,
+		StateHash: func(frame uint64, hash uint64) error {
+			if frame != uint64(len(hashes)+1) {
+				return fmt.Errorf("wrong frame number: %d", frame)
+			}
+
+			hashes = append(hashes, hash)
+			return nil
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		clock.Advance(10 * time.Millisecond)
+		err = loop.Tick()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return hashes, log
+}
+
+func TestDeterministicSceneUpdate(t *testing.T) {
+	hashes, log := runDeterminismTest(t, 0)
+	otherHashes, otherLog := runDeterminismTest(t, 0)
+
+	if len(hashes) != 5 || !reflect.DeepEqual(hashes, otherHashes) {
+		t.Fatalf("the runs diverge: %v, %v", hashes, otherHashes)
+	}
+
+	if !reflect.DeepEqual(log, otherLog) {
+		t.Fatal("the update orders diverge")
+	}
+
+	// The game objects spawned at the same Z
+	// are updated in the order they were spawned.
+	// The first one is destroyed by the spawner
+	// before it's updated.
+	expected := []string{}
+
+	for i := 1; i < 16; i++ {
+		expected = append(expected, fmt.Sprintf("spawn-1-%d", i))
+	}
+
+	if !reflect.DeepEqual(log[:15], expected) {
+		t.Fatalf("wrong update order: %v", log[:15])
+	}
+
+	divergedHashes, _ := runDeterminismTest(t, 1)
+
+	if divergedHashes[1] == hashes[1] {
+		t.Fatal("the divergence is not detected")
+	}
+}
+
+// holder is a test component which
+// holds the pointers to its data.
+type holder struct {
+	BaseComponent
+	Value int
+	Data  *int
+	Items []*int
+}
+
+func (h *holder) TypeID() string {
+	return "engine__Holder"
+}
+
+func TestStateHashSkipsPointers(t *testing.T) {
+	compTypeRegistry["engine__Holder"] = ComponentTypeEntry{
+		Name:        "Holder",
+		PkgPath:     "github.com/alacrity-engine/core/engine",
+		Constructor: func() Component { return &holder{} },
+		Fields: map[string]ComponentTypeFieldEntry{
+			"Value": {
+				Name:   "Value",
+				Type:   "int",
+				Getter: func(comp Component) interface{} { return comp.(*holder).Value },
+			},
+			"Data": {
+				Name:   "Data",
+				Type:   "*int",
+				Getter: func(comp Component) interface{} { return comp.(*holder).Data },
+			},
+			"Items": {
+				Name:   "Items",
+				Type:   "[]*int",
+				Getter: func(comp Component) interface{} { return comp.(*holder).Items },
+			},
+		},
+	}
+
+	t.Cleanup(func() {
+		delete(compTypeRegistry, "engine__Holder")
+	})
+
+	hashes := []uint64{}
+
+	for _, value := range []int{1, 1, 2} {
+		scene := newTestScene(t, "pointers")
+		gmob := NewGameObject(nil, "holder", nil)
+		data := value
+		comp := &holder{
+			Value: value,
+			Data:  &data,
+			Items: []*int{&data},
+		}
+		comp.SetActive(true)
+
+		err := gmob.AddComponent(comp, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = scene.AddGameObject(gmob, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hash, err := scene.StateHash()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	if hashes[0] != hashes[1] {
+		t.Fatal("the hash depends on the pointer addresses")
+	}
+
+	if hashes[0] == hashes[2] {
+		t.Fatal("the divergence is not detected")
+	}
+}
+
+// resourceHolder is a test component
+// which holds the resources.
+type resourceHolder struct {
+	BaseComponent
+	Font    *truetype.Font
+	Texture *render.Texture
+	Peer    *holder
+}
+
+func (h *resourceHolder) TypeID() string {
+	return "engine__ResourceHolder"
+}
+
+func TestStateHashResources(t *testing.T) {
+	compTypeRegistry["engine__ResourceHolder"] = ComponentTypeEntry{
+		Name:        "ResourceHolder",
+		PkgPath:     "github.com/alacrity-engine/core/engine",
+		Constructor: func() Component { return &resourceHolder{} },
+		Fields: map[string]ComponentTypeFieldEntry{
+			"Font": {
+				Name:   "Font",
+				Type:   "*truetype.Font",
+				Getter: func(comp Component) interface{} { return comp.(*resourceHolder).Font },
+			},
+			"Texture": {
+				Name:   "Texture",
+				Type:   "*render.Texture",
+				Getter: func(comp Component) interface{} { return comp.(*resourceHolder).Texture },
+			},
+			"Peer": {
+				Name:   "Peer",
+				Type:   "*engine.holder",
+				Getter: func(comp Component) interface{} { return comp.(*resourceHolder).Peer },
+			},
+		},
+	}
+
+	t.Cleanup(func() {
+		delete(compTypeRegistry, "engine__ResourceHolder")
+	})
+
+	scene := newTestScene(t, "resources")
+	loader := newTestFontLoader(t)
+	scene.resourceLoaders["fonts"] = loader
+
+	font, err := loader.LoadFont("regular")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The texture was not loaded by
+	// the scene, so it's skipped.
+	comp := &resourceHolder{Texture: &render.Texture{}}
+	comp.SetActive(true)
+	gmob := NewGameObject(nil, GenerateGameObjectName("holder"), nil)
+	err = gmob.AddComponent(comp, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.AddGameObject(gmob, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withoutFont, err := scene.StateHash()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comp.Font = font
+	withFont, err := scene.StateHash()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if withFont == withoutFont {
+		t.Fatal("the font loaded by the scene is not hashed")
+	}
+
+	// The numbers of the generated
+	// names are not hashed.
+	renamed := NewGameObject(nil, GenerateGameObjectName("holder"), nil)
+	other := newTestScene(t, "resources")
+	other.resourceLoaders["fonts"] = loader
+	otherComp := &resourceHolder{Font: font, Texture: &render.Texture{}}
+	otherComp.SetActive(true)
+	err = renamed.AddComponent(otherComp, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = other.AddGameObject(renamed, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherHash, err := other.StateHash()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if otherHash != withFont {
+		t.Fatal("the hash depends on the numbers of the generated names")
+	}
+
+	// The component detached from
+	// any game object cannot be hashed.
+	comp.Peer = &holder{}
+
+	if _, err := scene.StateHash(); err == nil {
+		t.Fatal("no error for the detached component")
+	}
+}
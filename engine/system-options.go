@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"fmt"
+)
+
+// SystemOption changes the way the
+// system is executed on the scene.
+type SystemOption func(params *systemParameters) error
+
+// systemParameters holds the parameters
+// of the system execution.
+type systemParameters struct {
+	order        int
+	dependencies []string
+}
+
+// SystemOptionWithOrder sets the order value of the
+// system. The systems with lower values are executed
+// earlier unless their dependencies require otherwise.
+// The default value is 0.
+func SystemOptionWithOrder(order int) SystemOption {
+	return func(params *systemParameters) error {
+		params.order = order
+		return nil
+	}
+}
+
+// SystemOptionWithDependencies makes the system be
+// executed after the systems with the specified names.
+func SystemOptionWithDependencies(names ...string) SystemOption {
+	return func(params *systemParameters) error {
+		for _, name := range names {
+			if name == "" {
+				return fmt.Errorf("the dependency name is empty")
+			}
+		}
+
+		params.dependencies = append(params.dependencies, names...)
+		return nil
+	}
+}
@@ -1,5 +1,114 @@
 package engine
 
+import (
+	"fmt"
+	"sort"
+)
+
 // System stores data
 // shared between its components.
+//
+// A system may implement any of the
+// lifecycle interfaces below to be
+// started, updated and destroyed
+// along with the scene.
 type System interface{}
+
+// StartableSystem is a system which is started
+// before the game objects of the scene.
+type StartableSystem interface {
+	Start() error
+}
+
+// UpdatableSystem is a system which is updated
+// once per frame before the game objects.
+type UpdatableSystem interface {
+	Update(deltaTime float64) error
+}
+
+// LateUpdatableSystem is a system which is
+// updated once per frame after the game objects
+// are late updated.
+type LateUpdatableSystem interface {
+	LateUpdate() error
+}
+
+// DestroyableSystem is a system which is notified
+// when it's removed from the scene or the scene
+// is unloaded.
+type DestroyableSystem interface {
+	Destroy() error
+}
+
+// systemEntry is a system of the scene
+// along with its ordering parameters.
+type systemEntry struct {
+	name         string
+	system       System
+	order        int
+	dependencies []string
+	seq          uint64
+	started      bool
+}
+
+// sortSystems returns the systems in the order they
+// must be executed: each system goes after all its
+// dependencies, and the independent systems are
+// ordered by their order values and then by the
+// order they were added in.
+func sortSystems(systems map[string]*systemEntry) ([]*systemEntry, error) {
+	pending := map[string]int{}
+	dependents := map[string][]*systemEntry{}
+	ready := []*systemEntry{}
+
+	for _, entry := range systems {
+		for _, dependency := range entry.dependencies {
+			if _, ok := systems[dependency]; !ok {
+				return nil, fmt.Errorf("system '%s' depends on absent system '%s'",
+					entry.name, dependency)
+			}
+
+			dependents[dependency] = append(dependents[dependency], entry)
+		}
+
+		pending[entry.name] = len(entry.dependencies)
+
+		if len(entry.dependencies) <= 0 {
+			ready = append(ready, entry)
+		}
+	}
+
+	less := func(a, b *systemEntry) bool {
+		if a.order != b.order {
+			return a.order < b.order
+		}
+
+		return a.seq < b.seq
+	}
+
+	sorted := make([]*systemEntry, 0, len(systems))
+
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			return less(ready[i], ready[j])
+		})
+
+		entry := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, entry)
+
+		for _, dependent := range dependents[entry.name] {
+			pending[dependent.name]--
+
+			if pending[dependent.name] <= 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(sorted) < len(systems) {
+		return nil, fmt.Errorf("the systems have cyclic dependencies")
+	}
+
+	return sorted, nil
+}
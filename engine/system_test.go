@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alacrity-engine/core/system"
+)
+
+// lifecycleSystem is a test system
+// which records its lifecycle hooks.
+type lifecycleSystem struct {
+	name string
+	log  *[]string
+}
+
+func (sys *lifecycleSystem) Start() error {
+	*sys.log = append(*sys.log, "start "+sys.name)
+	return nil
+}
+
+func (sys *lifecycleSystem) Update(deltaTime float64) error {
+	*sys.log = append(*sys.log, "update "+sys.name)
+	return nil
+}
+
+func (sys *lifecycleSystem) LateUpdate() error {
+	*sys.log = append(*sys.log, "late "+sys.name)
+	return nil
+}
+
+func (sys *lifecycleSystem) Destroy() error {
+	*sys.log = append(*sys.log, "destroy "+sys.name)
+	return nil
+}
+
+func TestSystemLifecycleAndOrder(t *testing.T) {
+	system.SetDeltaTime(0)
+	log := []string{}
+	scene := newTestScene(t, "systems")
+
+	gmob := NewGameObject(nil, "gmob", nil)
+	err := gmob.AddComponent(newProbe("component", &log), 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.AddGameObject(gmob, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Physics depends on input, AI goes first
+	// by order and audio has no hooks to call.
+	for _, entry := range []struct {
+		name    string
+		system  System
+		options []SystemOption
+	}{
+		{"input", &lifecycleSystem{"input", &log}, nil},
+		{"physics", &lifecycleSystem{"physics", &log},
+			[]SystemOption{SystemOptionWithDependencies("input"),
+				SystemOptionWithOrder(-10)}},
+		{"ai", &lifecycleSystem{"ai", &log},
+			[]SystemOption{SystemOptionWithOrder(-1)}},
+		{"audio", struct{}{}, nil},
+	} {
+		err := scene.AddSystem(entry.name, entry.system, entry.options...)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = scene.start()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"start ai", "start input", "start physics",
+		"update ai", "update input", "update physics",
+		"component",
+		"late ai", "late input", "late physics",
+	}
+
+	if !reflect.DeepEqual(log, expected) {
+		t.Fatalf("wrong system order: %v", log)
+	}
+
+	err = scene.RemoveSystem("input")
+
+	if err == nil {
+		t.Fatal("no error for removing the dependency")
+	}
+
+	err = scene.AddSystem("cycle", &lifecycleSystem{"cycle", &log},
+		SystemOptionWithDependencies("cycle"))
+
+	if err == nil {
+		t.Fatal("no error for the system depending on itself")
+	}
+
+	log = []string{}
+	_, _, err = scene.unload()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = []string{"destroy physics", "destroy input", "destroy ai"}
+
+	if !reflect.DeepEqual(log, expected) {
+		t.Fatalf("wrong destruction order: %v", log)
+	}
+
+	if _, err := scene.FindSystem("ai"); err == nil {
+		t.Fatal("the system is not removed")
+	}
+}
+
+func TestSystemDependencyValidation(t *testing.T) {
+	scene := newTestScene(t, "dependencies")
+
+	// The dependencies must be
+	// added before the dependents.
+	err := scene.AddSystem("first", struct{}{},
+		SystemOptionWithDependencies("second"))
+
+	if err == nil {
+		t.Fatal("no error for the absent dependency")
+	}
+
+	if _, err := scene.FindSystem("first"); err == nil {
+		t.Fatal("the rejected system is added")
+	}
+
+	err = scene.AddSystem("second", struct{}{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.AddSystem("first", struct{}{},
+		SystemOptionWithDependencies("second"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = scene.Update()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
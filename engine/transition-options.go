@@ -0,0 +1,44 @@
+package engine
+
+import "fmt"
+
+// SceneTransitionOption is an optional
+// parameter of the scene switch.
+type SceneTransitionOption func(params *sceneTransitionParameters) error
+
+// sceneTransitionParameters are the optional
+// parameters of the scene switch.
+type sceneTransitionParameters struct {
+	beforeUnload func(from, to *Scene) error
+	afterStart   func(from, to *Scene) error
+}
+
+// SceneTransitionOptionWithBeforeUnload sets the
+// callback called right before the current scene
+// is unloaded within the same frame.
+func SceneTransitionOptionWithBeforeUnload(beforeUnload func(from, to *Scene) error) SceneTransitionOption {
+	return func(params *sceneTransitionParameters) error {
+		if beforeUnload == nil {
+			return fmt.Errorf("the before-unload callback is nil")
+		}
+
+		params.beforeUnload = beforeUnload
+
+		return nil
+	}
+}
+
+// SceneTransitionOptionWithAfterStart sets the
+// callback called right after the other scene
+// is started within the same frame.
+func SceneTransitionOptionWithAfterStart(afterStart func(from, to *Scene) error) SceneTransitionOption {
+	return func(params *sceneTransitionParameters) error {
+		if afterStart == nil {
+			return fmt.Errorf("the after-start callback is nil")
+		}
+
+		params.afterStart = afterStart
+
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	cmath "github.com/alacrity-engine/core/math"
+	"github.com/alacrity-engine/core/system/collections"
+)
+
+// ZUpdateKey is a key to order game objects
+// in the scene update buffer. Game objects are
+// sorted by their Z update coordinate, and the
+// ones with the same Z are sorted in the order
+// of their insertion.
+type ZUpdateKey struct {
+	Z   cmath.Fixed
+	Seq uint64
+}
+
+// Less returns true if the key precedes the other key.
+func (key ZUpdateKey) Less(other collections.Comparable) bool {
+	otherKey := other.(ZUpdateKey)
+
+	if key.Z.Equal(otherKey.Z) {
+		return key.Seq < otherKey.Seq
+	}
+
+	return key.Z.Less(otherKey.Z)
+}
+
+// Greater returns true if the key follows the other key.
+func (key ZUpdateKey) Greater(other collections.Comparable) bool {
+	otherKey := other.(ZUpdateKey)
+
+	if key.Z.Equal(otherKey.Z) {
+		return key.Seq > otherKey.Seq
+	}
+
+	return key.Z.Greater(otherKey.Z)
+}
+
+// Equal returns true if the keys are the same.
+func (key ZUpdateKey) Equal(other collections.Comparable) bool {
+	otherKey := other.(ZUpdateKey)
+
+	return key.Z.Equal(otherKey.Z) && key.Seq == otherKey.Seq
+}
+
+// zUpdateKey returns the key of the game
+// object in the scene update buffer.
+func (gmob *GameObject) zUpdateKey() ZUpdateKey {
+	return newZUpdateKey(gmob.zUpdate, gmob.updateSeq)
+}
+
+// newZUpdateKey creates a new key out of the
+// Z update coordinate and the sequence number.
+func newZUpdateKey(zUpd float32, seq uint64) ZUpdateKey {
+	return ZUpdateKey{
+		Z:   cmath.FixedFromFloat32(zUpd),
+		Seq: seq,
+	}
+}
@@ -9,9 +9,6 @@ import (
 )
 
 func main() {
-	gob.Register(definitions.GameObjectPointer{})
-	gob.Register(definitions.ComponentPointer{})
-
 	prefab := &definitions.Prefab{
 		Name: "player",
 		TransformRoot: &definitions.TransformDefinition{
@@ -21,6 +18,7 @@ func main() {
 			Gmob: &definitions.GameObjectDefinition{
 				Name:    "player",
 				ZUpdate: 14,
+				Active:  true,
 				Components: []*definitions.ComponentDefinition{
 					{
 						TypeName: "danmaku__player__Health",
@@ -18,6 +18,7 @@ type Transform struct {
 	scale    Vec
 	children []*Transform
 	z        float32
+	owner    interface{}
 }
 
 func (t *Transform) AdjustZ(z float32) *Transform {
@@ -48,19 +49,46 @@ func (t *Transform) Parent() *Transform {
 	return t.parent
 }
 
+// Owner returns the object the
+// transform belongs to, if any.
+func (t *Transform) Owner() interface{} {
+	return t.owner
+}
+
+// SetOwner makes the transform belong to the
+// object, e.g. a game object, so the object can
+// be found by the transform in the hierarchy.
+func (t *Transform) SetOwner(owner interface{}) {
+	t.owner = owner
+}
+
 // SetParent sets the parent for the transform.
+// The transform is detached from its previous parent.
 func (t *Transform) SetParent(parent *Transform) error {
 	if parent == nil {
 		if t.parent != nil {
-			t.parent.RemoveChild(t)
+			return t.parent.RemoveChild(t)
 		}
 
 		return nil
 	}
 
+	if parent == t.parent {
+		return nil
+	}
+
 	return parent.AddChild(t)
 }
 
+// Children returns the direct
+// children of the transform.
+func (t *Transform) Children() []*Transform {
+	children := make([]*Transform, len(t.children))
+	copy(children, t.children)
+
+	return children
+}
+
 // HasChild returns true if the transform has 'tr'
 // as a direct child.
 func (t *Transform) HasChild(tr *Transform) bool {
@@ -70,12 +98,29 @@ func (t *Transform) HasChild(tr *Transform) bool {
 }
 
 // AddChild adds a new child to the transform.
+// The child is detached from its previous parent,
+// so it's never listed as a child of two transforms.
 func (t *Transform) AddChild(child *Transform) error {
 	if t.HasChild(child) {
 		return fmt.Errorf("the transform already has child '%v'",
 			child)
 	}
 
+	for ancestor := t; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor == child {
+			return fmt.Errorf("the transform cannot be a child of its descendant '%v'",
+				t)
+		}
+	}
+
+	if child.parent != nil {
+		err := child.parent.RemoveChild(child)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	t.children = append(t.children, child)
 	child.parent = t
 
@@ -244,10 +289,20 @@ func (t *Transform) ApplyScale(factor Vec) *Transform {
 }
 
 // NewTransform creates a new empty transform out of given data.
+// The new transform is added to the children of the parent,
+// so the parent and the child always agree on the hierarchy.
 func NewTransform(parent *Transform) *Transform {
-	return &Transform{
-		parent:   parent,
+	t := &Transform{
 		model:    mgl32.Ident4(),
 		children: []*Transform{},
 	}
+
+	// The new transform can't be a child of
+	// any transform, so it's attached directly.
+	if parent != nil {
+		parent.children = append(parent.children, t)
+		t.parent = parent
+	}
+
+	return t
 }
@@ -0,0 +1,38 @@
+package geometry
+
+import "testing"
+
+func TestNewTransformIsChildOfParent(t *testing.T) {
+	parent := NewTransform(nil)
+	child := NewTransform(parent)
+
+	if child.Parent() != parent || !parent.HasChild(child) {
+		t.Fatal("the new transform is not a child of its parent")
+	}
+}
+
+func TestAddChildDetachesFromOldParent(t *testing.T) {
+	oldParent := NewTransform(nil)
+	newParent := NewTransform(nil)
+	child := NewTransform(oldParent)
+
+	err := newParent.AddChild(child)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if oldParent.HasChild(child) {
+		t.Fatal("the child is not detached from its old parent")
+	}
+
+	if child.Parent() != newParent || !newParent.HasChild(child) {
+		t.Fatal("the child is not attached to its new parent")
+	}
+
+	// The transform cannot become
+	// a child of its descendant.
+	if err := child.AddChild(newParent); err == nil {
+		t.Fatal("no error for the cycle in the hierarchy")
+	}
+}
@@ -43,6 +43,10 @@ func (batch *Batch) Name() string {
 	return batch.name
 }
 
+func (batch *Batch) Canvas() *Canvas {
+	return batch.canvas
+}
+
 func (batch *Batch) buildVAO() {
 	gl.BindVertexArray(batch.glHandler)
 	defer gl.BindVertexArray(0)
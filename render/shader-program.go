@@ -20,6 +20,28 @@ func (program *ShaderProgram) Use() {
 	gl.UseProgram(program.glHandler)
 }
 
+func (program *ShaderProgram) Delete() {
+	gl.DeleteProgram(program.glHandler)
+}
+
+// Binary returns the format and the data of the
+// linked program binary to be cached and loaded
+// with NewShaderProgramFromBinary later.
+func (program *ShaderProgram) Binary() (uint32, []byte, error) {
+	var length int32
+	gl.GetProgramiv(program.glHandler, gl.PROGRAM_BINARY_LENGTH, &length)
+
+	if length <= 0 {
+		return 0, nil, fmt.Errorf("the program binary is not available")
+	}
+
+	binary := make([]byte, length)
+	var format uint32
+	gl.GetProgramBinary(program.glHandler, length, &length, &format, gl.Ptr(binary))
+
+	return format, binary[:length], nil
+}
+
 func (program *ShaderProgram) SetInt(name string, value int) {
 	location := gl.GetUniformLocation(program.glHandler, gl.Str(name+"\x00"))
 	gl.Uniform1i(location, int32(value))
@@ -64,6 +86,7 @@ func NewShaderProgramFromShaders(vertexShader, fragmentShader *Shader) (*ShaderP
 
 	gl.AttachShader(program, vertexShader.glHandler)
 	gl.AttachShader(program, fragmentShader.glHandler)
+	gl.ProgramParameteri(program, gl.PROGRAM_BINARY_RETRIEVABLE_HINT, gl.TRUE)
 	gl.LinkProgram(program)
 
 	var status int32
@@ -86,6 +109,30 @@ func NewShaderProgramFromShaders(vertexShader, fragmentShader *Shader) (*ShaderP
 	}, nil
 }
 
+// NewShaderProgramFromBinary loads the program from
+// the binary returned by the Binary method. The binary
+// can only be loaded by the same driver it was built by.
+func NewShaderProgramFromBinary(format uint32, binary []byte) (*ShaderProgram, error) {
+	if len(binary) == 0 {
+		return nil, fmt.Errorf("the program binary is empty")
+	}
+
+	program := gl.CreateProgram()
+	gl.ProgramBinary(program, format, gl.Ptr(binary), int32(len(binary)))
+
+	var status int32
+	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
+
+	if status == gl.FALSE {
+		gl.DeleteProgram(program)
+		return nil, fmt.Errorf("failed to load the program binary")
+	}
+
+	return &ShaderProgram{
+		glHandler: program,
+	}, nil
+}
+
 func NewStandardSpriteShaderProgram() (*ShaderProgram, error) {
 	vertexShader, err := NewStandardSpriteShader(ShaderTypeVertex)
 
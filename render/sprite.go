@@ -28,7 +28,9 @@ type Sprite struct {
 	targetArea                        geometry.Rect
 	texture                           *Texture
 	shaderProgram                     *ShaderProgram
+	vertexDrawMode                    DrawMode
 	drawMode                          DrawMode
+	colorDrawMode                     DrawMode
 	drawZ                             float32 // drawZ must be in the range of [zMin; zMax]
 	canvas                            *Canvas
 	batch                             *Batch
@@ -39,6 +41,10 @@ func (sprite *Sprite) Texture() *Texture {
 	return sprite.texture
 }
 
+func (sprite *Sprite) ShaderProgram() *ShaderProgram {
+	return sprite.shaderProgram
+}
+
 func (sprite *Sprite) ColorMask() ColorMask {
 	return sprite.colorMask
 }
@@ -47,6 +53,28 @@ func (sprite *Sprite) TargetArea() geometry.Rect {
 	return sprite.targetArea
 }
 
+func (sprite *Sprite) VertexDrawMode() DrawMode {
+	return sprite.vertexDrawMode
+}
+
+// DrawMode returns the draw mode of
+// the texture coordinates buffer.
+func (sprite *Sprite) DrawMode() DrawMode {
+	return sprite.drawMode
+}
+
+func (sprite *Sprite) ColorDrawMode() DrawMode {
+	return sprite.colorDrawMode
+}
+
+func (sprite *Sprite) Canvas() *Canvas {
+	return sprite.canvas
+}
+
+func (sprite *Sprite) Batch() *Batch {
+	return sprite.batch
+}
+
 func (sprite *Sprite) createVertexBuffer() {
 	var vertexBufferHandler uint32
 	gl.GenBuffers(1, &vertexBufferHandler)
@@ -258,6 +286,8 @@ func (sprite *Sprite) SetColorMask(colorMask ColorMask) error {
 		gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(colorMask)*4*4, gl.Ptr(data[:]))
 		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 
+		sprite.colorMask = colorMask
+
 		return nil
 	}
 
@@ -303,6 +333,8 @@ func (sprite *Sprite) SetTargetArea(targetArea geometry.Rect) error {
 		gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(vertices)*4, gl.Ptr(vertices))
 		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 
+		sprite.targetArea = targetArea
+
 		return nil
 	}
 
@@ -488,7 +520,9 @@ func NewSpriteFromTextureAndProgram(vertexDrawMode, textureDrawMode, colorDrawMo
 		targetArea:                        targetArea,
 		colorMask:                         colorMask,
 		shaderProgram:                     shaderProgram,
+		vertexDrawMode:                    vertexDrawMode,
 		drawMode:                          textureDrawMode,
+		colorDrawMode:                     colorDrawMode,
 		batchIndex:                        -1,
 	}, nil
 }
@@ -25,6 +25,10 @@ func (texture *Texture) Use() {
 	gl.BindTexture(gl.TEXTURE_2D, texture.glHandler)
 }
 
+func (texture *Texture) Delete() {
+	gl.DeleteTextures(1, &texture.glHandler)
+}
+
 func NewTextureFromImage(img *image.RGBA, filter TextureFiltering) *Texture {
 	var handler uint32
 
@@ -0,0 +1,190 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/render"
+	"github.com/alacrity-engine/core/tasking"
+)
+
+// The asynchronous loaders read and decode the
+// resources on the worker goroutines. OpenGL is only
+// accessed on the main thread, so the workers marshal the
+// uploads onto the main thread through the main thread work
+// queue. The loading processes are completed by
+// ProcessMainThreadQueue, so their results can be used
+// right away on the main thread.
+
+// completeProcess sets the result or the error
+// of the loading process and completes it.
+func completeProcess(process *tasking.AsynchronousProcess, result interface{}, err error) {
+	if err != nil {
+		process.SetError(err)
+	} else {
+		process.SetResult(result)
+	}
+
+	process.SetProgress(100)
+}
+
+// completeOnMainThread completes the loading
+// process on the main thread.
+func completeOnMainThread(process *tasking.AsynchronousProcess, result interface{}, err error) {
+	queueMainThreadWork(func() {
+		completeProcess(process, result, err)
+	})
+}
+
+// decodeTexture reads the texture data and decodes the
+// picture of the texture unless the texture is already
+// uploaded. The returned function uploads the texture
+// to the GPU, so it must be called on the main thread.
+//
+// Must be called from a worker goroutine.
+func (loader *ResourceLoader) decodeTexture(name string) (func() (*render.Texture, error), error) {
+	texture, ok := acquireResource(loader.buffer,
+		loader.buffer.textures, definitions.ResourceTypeTexture, name)
+
+	if ok {
+		return func() (*render.Texture, error) {
+			return texture, nil
+		}, nil
+	}
+
+	texData, err := loader.readTextureData(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pic, err := loader.LoadPicture(texData.PictureID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (*render.Texture, error) {
+		return loader.uploadTexture(name, texData, pic)
+	}, nil
+}
+
+// LoadTextureAsync starts loading the texture on a worker
+// goroutine. The picture of the texture is decoded on the
+// worker, and the texture is uploaded to the GPU on the main
+// thread. The result of the process is *render.Texture.
+func (loader *ResourceLoader) LoadTextureAsync(name string) *tasking.AsynchronousProcess {
+	process := tasking.NewAsynchronousProcess(
+		fmt.Sprintf("load texture '%s'", name))
+	texture, ok := acquireResource(loader.buffer,
+		loader.buffer.textures, definitions.ResourceTypeTexture, name)
+
+	if ok {
+		completeProcess(process, texture, nil)
+		return process
+	}
+
+	go func() {
+		upload, err := loader.decodeTexture(name)
+
+		if err != nil {
+			completeOnMainThread(process, nil, err)
+			return
+		}
+
+		process.SetProgress(50)
+
+		queueMainThreadWork(func() {
+			texture, err := upload()
+			completeProcess(process, texture, err)
+		})
+	}()
+
+	return process
+}
+
+// LoadAnimationAsync starts loading the animation and its
+// texture on a worker goroutine. The texture is uploaded to
+// the GPU on the main thread. The result of the process
+// is *anim.Animation.
+func (loader *ResourceLoader) LoadAnimationAsync(animID string) *tasking.AsynchronousProcess {
+	process := tasking.NewAsynchronousProcess(
+		fmt.Sprintf("load animation '%s'", animID))
+
+	go func() {
+		animData, err := loader.loadAnimationData(animID)
+
+		if err != nil {
+			completeOnMainThread(process, nil, err)
+			return
+		}
+
+		process.SetProgress(25)
+		upload, err := loader.decodeTexture(animData.TextureID)
+
+		if err != nil {
+			completeOnMainThread(process, nil, err)
+			return
+		}
+
+		process.SetProgress(50)
+
+		queueMainThreadWork(func() {
+			texture, err := upload()
+
+			if err != nil {
+				completeProcess(process, nil, err)
+				return
+			}
+
+			animation, err := loader.newAnimation(animID, animData, texture)
+			completeProcess(process, animation, err)
+		})
+	}()
+
+	return process
+}
+
+// LoadFontAsync starts loading and parsing the font
+// on a worker goroutine. The result of the process
+// is *truetype.Font.
+func (loader *ResourceLoader) LoadFontAsync(name string) *tasking.AsynchronousProcess {
+	process := tasking.NewAsynchronousProcess(
+		fmt.Sprintf("load font '%s'", name))
+	font, ok := acquireResource(loader.buffer,
+		loader.buffer.fonts, definitions.ResourceTypeFont, name)
+
+	if ok {
+		completeProcess(process, font, nil)
+		return process
+	}
+
+	go func() {
+		font, err := loader.LoadFont(name)
+		completeOnMainThread(process, font, err)
+	}()
+
+	return process
+}
+
+// LoadAudioAsync starts reading the audio on a
+// worker goroutine. The result of the process
+// is io.ReadCloser.
+func (loader *ResourceLoader) LoadAudioAsync(name string) *tasking.AsynchronousProcess {
+	process := tasking.NewAsynchronousProcess(
+		fmt.Sprintf("load audio '%s'", name))
+	audio, ok := acquireResource(loader.buffer,
+		loader.buffer.audio, definitions.ResourceTypeAudio, name)
+
+	if ok {
+		completeProcess(process, loader.newAudioStream(name, audio), nil)
+		return process
+	}
+
+	go func() {
+		audio, err := loader.LoadAudio(name)
+		completeOnMainThread(process, audio, err)
+	}()
+
+	return process
+}
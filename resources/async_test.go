@@ -0,0 +1,284 @@
+package resources
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/tasking"
+	"github.com/golang/freetype/truetype"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func newTestResourceLoader(t *testing.T, bucket string, entries map[string][]byte) *ResourceLoader {
+	t.Helper()
+
+	fname := filepath.Join(t.TempDir(), "resources.db")
+	db, err := bolt.Open(fname, 0666, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		buck, err := tx.CreateBucketIfNotExists([]byte(bucket))
+
+		if err != nil {
+			return err
+		}
+
+		for key, value := range entries {
+			err = buck.Put([]byte(key), value)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Close()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loader, err := NewResourceLoader(fname)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		loader.Close()
+	})
+
+	return loader
+}
+
+// waitForProcess processes the main thread queue
+// until the loading process is complete.
+func waitForProcess(t *testing.T, process *tasking.AsynchronousProcess) interface{} {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for process.CurrentProgress() < 100 {
+		if time.Now().After(deadline) {
+			t.Fatalf("the process '%s' is not complete", process.Name())
+		}
+
+		ProcessMainThreadQueue()
+		time.Sleep(time.Millisecond)
+	}
+
+	processErr, err := process.Error()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processErr != nil {
+		t.Fatal(processErr)
+	}
+
+	result, err := process.Result()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func TestLoadAudioAsync(t *testing.T) {
+	loader := newTestResourceLoader(t, "audio", map[string][]byte{
+		"jump": []byte("jump sound"),
+	})
+
+	// Both processes must share
+	// the buffered audio.
+	first := loader.LoadAudioAsync("jump")
+	second := loader.LoadAudioAsync("jump")
+
+	streams := []io.ReadCloser{}
+
+	for _, process := range []*tasking.AsynchronousProcess{first, second} {
+		stream := waitForProcess(t, process).(io.ReadCloser)
+		data, err := io.ReadAll(stream)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != "jump sound" {
+			t.Fatalf("wrong audio: %q", data)
+		}
+
+		streams = append(streams, stream)
+	}
+
+	// The buffered audio is
+	// returned immediately.
+	third := loader.LoadAudioAsync("jump")
+
+	if third.CurrentProgress() != 100 {
+		t.Fatal("the buffered audio is not returned immediately")
+	}
+
+	streams = append(streams, waitForProcess(t, third).(io.ReadCloser))
+
+	if refs := loader.References(definitions.ResourceTypeAudio, "jump"); refs != 3 {
+		t.Fatalf("wrong number of references: %d", refs)
+	}
+
+	// Closing the streams releases the audio,
+	// and closing them again does nothing.
+	for i := 0; i < 2; i++ {
+		for _, stream := range streams {
+			err := stream.Close()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if refs := loader.References(definitions.ResourceTypeAudio, "jump"); refs != 0 {
+		t.Fatalf("the audio is not released: %d references", refs)
+	}
+
+	missing := loader.LoadAudioAsync("missing")
+
+	for missing.CurrentProgress() < 100 {
+		ProcessMainThreadQueue()
+		time.Sleep(time.Millisecond)
+	}
+
+	processErr, err := missing.Error()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processErr == nil {
+		t.Fatal("no error for the missing audio")
+	}
+}
+
+// waitForProcessError processes the main thread
+// queue until the loading process is complete and
+// returns the error the process has failed with.
+func waitForProcessError(t *testing.T, process *tasking.AsynchronousProcess) error {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for process.CurrentProgress() < 100 {
+		if time.Now().After(deadline) {
+			t.Fatalf("the process '%s' is not complete", process.Name())
+		}
+
+		ProcessMainThreadQueue()
+		time.Sleep(time.Millisecond)
+	}
+
+	processErr, err := process.Error()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processErr == nil {
+		t.Fatalf("the process '%s' hasn't failed", process.Name())
+	}
+
+	return processErr
+}
+
+func TestLoadFontAsync(t *testing.T) {
+	loader := newTestResourceLoader(t, "fonts", map[string][]byte{
+		"regular": goregular.TTF,
+		"broken":  []byte("not a font"),
+	})
+
+	// Both processes must share
+	// the parsed font.
+	first := loader.LoadFontAsync("regular")
+	second := loader.LoadFontAsync("regular")
+	fonts := []*truetype.Font{}
+
+	for _, process := range []*tasking.AsynchronousProcess{first, second} {
+		font, ok := waitForProcess(t, process).(*truetype.Font)
+
+		if !ok || font == nil {
+			t.Fatal("the result is not a font")
+		}
+
+		fonts = append(fonts, font)
+	}
+
+	if fonts[0] != fonts[1] {
+		t.Fatal("the font is parsed twice")
+	}
+
+	// The buffered font is
+	// returned immediately.
+	third := loader.LoadFontAsync("regular")
+
+	if third.CurrentProgress() != 100 {
+		t.Fatal("the buffered font is not returned immediately")
+	}
+
+	if waitForProcess(t, third) != fonts[0] {
+		t.Fatal("the buffered font is not returned")
+	}
+
+	if refs := loader.References(definitions.ResourceTypeFont, "regular"); refs != 3 {
+		t.Fatalf("wrong number of references: %d", refs)
+	}
+
+	for i := 0; i < 3; i++ {
+		err := loader.Release(fonts[0])
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if refs := loader.References(definitions.ResourceTypeFont, "regular"); refs != 0 {
+		t.Fatalf("the font is not released: %d references", refs)
+	}
+
+	// The failed loads don't
+	// reference the fonts.
+	for _, name := range []string{"missing", "broken"} {
+		err := waitForProcessError(t, loader.LoadFontAsync(name))
+
+		if !strings.Contains(err.Error(), "font '"+name+"'") {
+			t.Fatalf("wrong error for font '%s': %v", name, err)
+		}
+
+		if refs := loader.References(definitions.ResourceTypeFont, name); refs != 0 {
+			t.Fatalf("the failed font '%s' is referenced: %d references", name, refs)
+		}
+	}
+}
+
+func TestLoadFontAsyncWithoutBucket(t *testing.T) {
+	loader := newTestResourceLoader(t, "audio", nil)
+	err := waitForProcessError(t, loader.LoadFontAsync("regular"))
+
+	if !strings.Contains(err.Error(), "bucket 'fonts' not found") {
+		t.Fatalf("wrong error: %v", err)
+	}
+}
@@ -1,8 +1,14 @@
 package resources
 
 import (
+	"container/list"
 	"fmt"
+	"sort"
+	"sync"
 
+	"github.com/alacrity-engine/core/anim"
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/math/geometry"
 	"github.com/alacrity-engine/core/render"
 	codec "github.com/alacrity-engine/resource-codec"
 	"github.com/golang/freetype/truetype"
@@ -13,7 +19,26 @@ import (
 // loader will at first try to look up the resource
 // in the buffer and if it isn't loaded the loader
 // will take the resource from the resource file.
+//
+// The buffer is safe for concurrent use. Concurrent
+// requests for the same missing resource share
+// a single load.
+//
+// Pictures, textures, fonts and audio are reference
+// counted. When nothing references them, they're kept
+// in the buffer until the memory they occupy exceeds
+// the budget, and then they're evicted in the least
+// recently used order. The referenced resources
+// don't count against the budget.
 type resourceBuffer struct {
+	locker         *sync.RWMutex
+	loads          map[resourceKey]*resourceLoad
+	entries        map[resourceKey]*resourceEntry
+	unused         *list.List
+	usage          int64
+	unusedUsage    int64
+	budget         int64
+	ids            map[interface{}]resourceKey
 	pictures       map[string]*render.Picture
 	animations     map[string]*codec.AnimationData
 	fonts          map[string]*truetype.Font
@@ -21,78 +46,334 @@ type resourceBuffer struct {
 	textures       map[string]*render.Texture
 	shaders        map[string]*render.Shader
 	shaderPrograms map[string]*render.ShaderProgram
-	spritesheets   map[string]*codec.SpritesheetData
+	spritesheets   map[string]*definitions.SpritesheetDefinition
+	animationIDs   map[*anim.Animation]string
 }
 
-// putPicture puts the picture in the buffer.
-func (rb *resourceBuffer) putPicture(name string, pic *render.Picture) error {
-	if _, ok := rb.pictures[name]; ok {
-		return RaiseErrorPictureAlreadyExists(name)
+// resourceKey identifies the
+// resource in the buffer.
+type resourceKey struct {
+	resourceType string
+	id           string
+}
+
+// resourceLoad is a load of a resource
+// shared by all the concurrent requests
+// for the resource.
+type resourceLoad struct {
+	done chan struct{}
+	err  error
+}
+
+// resourceEntry tracks the references to the
+// buffered resource and the memory it uses.
+type resourceEntry struct {
+	key  resourceKey
+	refs int
+	size int64
+	// element is the element of the list of
+	// unused resources. It's nil while the
+	// resource is referenced.
+	element *list.Element
+}
+
+// loadResource returns the resource from the buffer.
+// If the resource is not buffered, it's loaded with
+// the function and put in the buffer. Only one load
+// of the resource is performed at a time, the other
+// requests wait for it and share its result. If the
+// load fails, nothing is buffered, so the next
+// request loads the resource again.
+//
+// If the size function is not nil, the resource is
+// reference counted and each call acquires a reference
+// to it. The function is called without the buffer
+// lock held.
+func loadResource[T any](
+	rb *resourceBuffer, resources map[string]T, resourceType, id string,
+	load func() (T, error), sizeOf func(T) int64,
+) (T, error) {
+	key := resourceKey{
+		resourceType: resourceType,
+		id:           id,
 	}
 
-	rb.pictures[name] = pic
+	for {
+		rb.locker.Lock()
 
-	return nil
+		if value, ok := resources[id]; ok {
+			rb.acquire(key)
+			rb.locker.Unlock()
+
+			return value, nil
+		}
+
+		if current, ok := rb.loads[key]; ok {
+			rb.locker.Unlock()
+			<-current.done
+
+			if current.err != nil {
+				var zero T
+				return zero, current.err
+			}
+
+			// Look the resource up once again
+			// to acquire a reference to it.
+			continue
+		}
+
+		current := &resourceLoad{
+			done: make(chan struct{}),
+		}
+		rb.loads[key] = current
+		rb.locker.Unlock()
+
+		value, err := load()
+
+		rb.locker.Lock()
+
+		if err == nil {
+			resources[id] = value
+			rb.index(key, value)
+
+			if sizeOf != nil {
+				rb.track(key, sizeOf(value))
+				rb.evict()
+			}
+		}
+
+		delete(rb.loads, key)
+		rb.locker.Unlock()
+
+		current.err = err
+		close(current.done)
+
+		return value, err
+	}
 }
 
-// takePicture takes the picture from the buffer.
-func (rb *resourceBuffer) takePicture(name string) (*render.Picture, error) {
-	if _, ok := rb.pictures[name]; !ok {
-		return nil, RaiseErrorPictureDoesntExist(name)
+// acquireResource returns the resource and acquires
+// a reference to it if the resource is buffered.
+func acquireResource[T any](rb *resourceBuffer, resources map[string]T, resourceType, id string) (T, bool) {
+	rb.locker.Lock()
+	defer rb.locker.Unlock()
+
+	value, ok := resources[id]
+
+	if ok {
+		rb.acquire(resourceKey{
+			resourceType: resourceType,
+			id:           id,
+		})
 	}
 
-	return rb.pictures[name], nil
+	return value, ok
 }
 
-func (rb *resourceBuffer) putSpritesheet(name string, ss *codec.SpritesheetData) error {
-	if _, ok := rb.spritesheets[name]; ok {
-		return fmt.Errorf("the '%s' spritesheet already exists", name)
+// acquire adds a reference to the resource
+// if the resource is reference counted.
+//
+// Must be called with the lock held.
+func (rb *resourceBuffer) acquire(key resourceKey) {
+	entry, ok := rb.entries[key]
+
+	if !ok {
+		return
 	}
 
-	rb.spritesheets[name] = ss
+	entry.refs++
 
-	return nil
+	if entry.element != nil {
+		rb.unused.Remove(entry.element)
+		entry.element = nil
+		rb.unusedUsage -= entry.size
+	}
 }
 
-func (rb *resourceBuffer) takeSpritesheet(name string) (*codec.SpritesheetData, error) {
-	if _, ok := rb.spritesheets[name]; !ok {
-		return nil, RaiseErrorSpritesheetDoesntExist(name)
+// index remembers the key of the resource, so
+// the resource can be found by its pointer. The
+// audio and the animation data are not indexed
+// as the loader returns other values for them.
+//
+// Must be called with the lock held.
+func (rb *resourceBuffer) index(key resourceKey, resource interface{}) {
+	switch resource.(type) {
+	case *render.Texture, *render.Picture, *truetype.Font,
+		*render.Shader, *render.ShaderProgram:
+		rb.ids[resource] = key
 	}
+}
 
-	return rb.spritesheets[name], nil
+// track starts counting the references to the
+// newly buffered resource. The resource is
+// referenced once by the caller.
+//
+// Must be called with the lock held.
+func (rb *resourceBuffer) track(key resourceKey, size int64) {
+	rb.entries[key] = &resourceEntry{
+		key:  key,
+		refs: 1,
+		size: size,
+	}
+	rb.usage += size
 }
 
-func (rb *resourceBuffer) putTexture(name string, texture *render.Texture) error {
-	if _, ok := rb.textures[name]; ok {
-		return fmt.Errorf("the '%s' texture already exists", name)
+// release removes a reference to the resource.
+// When nothing references the resource, it
+// becomes a candidate for eviction.
+func (rb *resourceBuffer) release(key resourceKey) error {
+	rb.locker.Lock()
+	defer rb.locker.Unlock()
+
+	return rb.releaseLocked(key)
+}
+
+// releaseLocked removes a reference to the resource.
+//
+// Must be called with the lock held.
+func (rb *resourceBuffer) releaseLocked(key resourceKey) error {
+	entry, ok := rb.entries[key]
+
+	if !ok || entry.refs <= 0 {
+		return fmt.Errorf("the %s '%s' is not referenced",
+			key.resourceType, key.id)
 	}
 
-	rb.textures[name] = texture
+	entry.refs--
+
+	if entry.refs == 0 {
+		entry.element = rb.unused.PushFront(entry)
+		rb.unusedUsage += entry.size
+		rb.evict()
+	}
 
 	return nil
 }
 
-func (rb *resourceBuffer) takeTexture(name string) (*render.Texture, error) {
-	if _, ok := rb.textures[name]; !ok {
-		return nil, RaiseErrorTextureDoesntExist(name)
+// releaseAnimation removes the reference to
+// the texture of the animation created by
+// the loader and forgets the animation.
+func (rb *resourceBuffer) releaseAnimation(animation *anim.Animation) error {
+	rb.locker.Lock()
+	defer rb.locker.Unlock()
+
+	animID, ok := rb.animationIDs[animation]
+
+	if !ok {
+		return fmt.Errorf("the animation is not loaded by the loader")
 	}
 
-	return rb.textures[name], nil
+	delete(rb.animationIDs, animation)
+
+	return rb.releaseLocked(resourceKey{
+		resourceType: definitions.ResourceTypeTexture,
+		id:           rb.animations[animID].TextureID,
+	})
 }
 
-// putAnimation puts the animation in the buffer.
-func (rb *resourceBuffer) putAnimation(name string, anim *codec.AnimationData) error {
-	if _, ok := rb.animations[name]; ok {
-		return RaiseErrorAnimationAlreadyExists(name)
+// evict removes the least recently used resources
+// nothing references until the memory they occupy
+// fits in the budget.
+//
+// Must be called with the lock held.
+func (rb *resourceBuffer) evict() {
+	for rb.unusedUsage > rb.budget && rb.unused.Len() > 0 {
+		entry := rb.unused.Remove(rb.unused.Back()).(*resourceEntry)
+		delete(rb.entries, entry.key)
+		rb.usage -= entry.size
+		rb.unusedUsage -= entry.size
+
+		id := entry.key.id
+
+		switch entry.key.resourceType {
+		case definitions.ResourceTypePicture:
+			delete(rb.ids, rb.pictures[id])
+			delete(rb.pictures, id)
+
+		case definitions.ResourceTypeFont:
+			delete(rb.ids, rb.fonts[id])
+			delete(rb.fonts, id)
+
+		case definitions.ResourceTypeAudio:
+			delete(rb.audio, id)
+
+		case definitions.ResourceTypeTexture:
+			texture := rb.textures[id]
+			delete(rb.ids, texture)
+			delete(rb.textures, id)
+
+			// The buffer can be accessed from
+			// any goroutine, so the texture is
+			// deleted on the main thread by
+			// ProcessMainThreadQueue.
+			queueMainThreadWork(texture.Delete)
+		}
 	}
+}
+
+// setBudget sets the amount of memory the unused
+// resources can occupy and evicts the ones
+// which don't fit in it.
+func (rb *resourceBuffer) setBudget(budget int64) {
+	rb.locker.Lock()
+	defer rb.locker.Unlock()
+
+	rb.budget = budget
+	rb.evict()
+}
+
+// memoryUsage returns the amount of memory
+// occupied by the reference counted resources.
+func (rb *resourceBuffer) memoryUsage() int64 {
+	rb.locker.RLock()
+	defer rb.locker.RUnlock()
+
+	return rb.usage
+}
+
+// references returns the number
+// of references to the resource.
+func (rb *resourceBuffer) references(key resourceKey) int {
+	rb.locker.RLock()
+	defer rb.locker.RUnlock()
+
+	if entry, ok := rb.entries[key]; ok {
+		return entry.refs
+	}
+
+	return 0
+}
 
-	rb.animations[name] = anim
+// putTexture puts the texture in the buffer.
+// The texture is referenced once by the caller.
+//
+// Textures are only created on the main
+// thread, so they don't need a shared load.
+func (rb *resourceBuffer) putTexture(name string, texture *render.Texture, size int64) error {
+	rb.locker.Lock()
+	defer rb.locker.Unlock()
+
+	if _, ok := rb.textures[name]; ok {
+		return fmt.Errorf("the '%s' texture already exists", name)
+	}
+
+	key := resourceKey{
+		resourceType: definitions.ResourceTypeTexture,
+		id:           name,
+	}
+	rb.textures[name] = texture
+	rb.index(key, texture)
+	rb.track(key, size)
+	rb.evict()
 
 	return nil
 }
 
 // takeAnimation takes the animation from the buffer.
 func (rb *resourceBuffer) takeAnimation(name string) (*codec.AnimationData, error) {
+	rb.locker.RLock()
+	defer rb.locker.RUnlock()
+
 	if _, ok := rb.animations[name]; !ok {
 		return nil, RaiseErrorAnimationDoesntExist(name)
 	}
@@ -100,53 +381,89 @@ func (rb *resourceBuffer) takeAnimation(name string) (*codec.AnimationData, erro
 	return rb.animations[name], nil
 }
 
-// putFont puts the font in the buffer.
-func (rb *resourceBuffer) putFont(name string, fnt *truetype.Font) error {
-	if _, ok := rb.fonts[name]; ok {
-		return RaiseErrorFontAlreadyExists(name)
-	}
-
-	rb.fonts[name] = fnt
+// putAnimationID remembers the ID of
+// the animation created by the loader.
+func (rb *resourceBuffer) putAnimationID(animation *anim.Animation, animID string) {
+	rb.locker.Lock()
+	defer rb.locker.Unlock()
 
-	return nil
+	rb.animationIDs[animation] = animID
 }
 
-// takeFont takes the font from the buffer.
-func (rb *resourceBuffer) takeFont(name string) (*truetype.Font, error) {
-	if _, ok := rb.fonts[name]; !ok {
-		return nil, RaiseErrorFontDoesntExist(name)
+// findResourceID searches the buffer for the
+// resource and returns its type and ID.
+func (rb *resourceBuffer) findResourceID(resource interface{}) (string, string, bool) {
+	rb.locker.RLock()
+	defer rb.locker.RUnlock()
+
+	switch res := resource.(type) {
+	case *render.Texture, *render.Picture, *truetype.Font,
+		*render.Shader, *render.ShaderProgram:
+		if key, ok := rb.ids[res]; ok {
+			return key.resourceType, key.id, true
+		}
+
+	case *anim.Animation:
+		if id, ok := rb.animationIDs[res]; ok {
+			return definitions.ResourceTypeAnimation, id, true
+		}
+
+	case *Spritesheet:
+		if _, ok := rb.spritesheets[res.id]; ok {
+			return definitions.ResourceTypeSpritesheet, res.id, true
+		}
 	}
 
-	return rb.fonts[name], nil
+	return "", "", false
 }
 
-// putAudio puts the audio in the buffer.
-func (rb *resourceBuffer) putAudio(name string, audio []byte) error {
-	if _, ok := rb.audio[name]; ok {
-		return RaiseErrorAudioAlreadyExists(name)
-	}
+// findSpritesheetFrame returns the ID of the buffered
+// spritesheet of the texture and the name of its frame
+// occupying the area. The spritesheets are looked up
+// in the order of their IDs.
+func (rb *resourceBuffer) findSpritesheetFrame(textureID string, area geometry.Rect) (string, string, bool) {
+	rb.locker.RLock()
+	defer rb.locker.RUnlock()
 
-	rb.audio[name] = audio
+	ids := make([]string, 0, len(rb.spritesheets))
 
-	return nil
-}
+	for id, def := range rb.spritesheets {
+		if def.TextureID == textureID {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
 
-// takeAudio takes the audio from the buffer.
-func (rb *resourceBuffer) takeAudio(name string) ([]byte, error) {
-	if _, ok := rb.audio[name]; !ok {
-		return nil, RaiseErrorAudioDoesntExist(name)
+	for _, id := range ids {
+		for _, frame := range rb.spritesheets[id].Frames {
+			if frame.Name != "" && frame.Area == area {
+				return id, frame.Name, true
+			}
+		}
 	}
 
-	return rb.audio[name], nil
+	return "", "", false
 }
 
 // newResourceBuffer creates a new resource buffer
 // to store every resource ever loaded by the loader.
-func newResourceBuffer() *resourceBuffer {
+func newResourceBuffer(budget int64) *resourceBuffer {
 	return &resourceBuffer{
-		pictures:   map[string]*render.Picture{},
-		animations: map[string]*codec.AnimationData{},
-		fonts:      map[string]*truetype.Font{},
-		audio:      map[string][]byte{},
+		locker:         new(sync.RWMutex),
+		loads:          map[resourceKey]*resourceLoad{},
+		entries:        map[resourceKey]*resourceEntry{},
+		unused:         list.New(),
+		budget:         budget,
+		ids:            map[interface{}]resourceKey{},
+		pictures:       map[string]*render.Picture{},
+		animations:     map[string]*codec.AnimationData{},
+		fonts:          map[string]*truetype.Font{},
+		audio:          map[string][]byte{},
+		textures:       map[string]*render.Texture{},
+		shaders:        map[string]*render.Shader{},
+		shaderPrograms: map[string]*render.ShaderProgram{},
+		spritesheets:   map[string]*definitions.SpritesheetDefinition{},
+		animationIDs:   map[*anim.Animation]string{},
 	}
 }
@@ -0,0 +1,250 @@
+package resources
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/golang/freetype/truetype"
+)
+
+func audioSize(audio []byte) int64 {
+	return int64(len(audio))
+}
+
+func loadTestAudio(buffer *resourceBuffer, name string, data string) ([]byte, error) {
+	return loadResource(buffer, buffer.audio,
+		definitions.ResourceTypeAudio, name,
+		func() ([]byte, error) {
+			return []byte(data), nil
+		}, audioSize)
+}
+
+func TestBufferSharesConcurrentLoads(t *testing.T) {
+	buffer := newResourceBuffer(DefaultMemoryBudget)
+	release := make(chan struct{})
+	var loads int32
+
+	load := func() ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+
+		return []byte("jump sound"), nil
+	}
+
+	const requests = 8
+	results := make([][]byte, requests)
+	errs := make([]error, requests)
+	var wg sync.WaitGroup
+
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			results[i], errs[i] = loadResource(buffer, buffer.audio,
+				definitions.ResourceTypeAudio, "jump", load, audioSize)
+		}(i)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if loads != 1 {
+		t.Fatalf("the audio is loaded %d times", loads)
+	}
+
+	for i := 0; i < requests; i++ {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+
+		if &results[i][0] != &results[0][0] {
+			t.Fatal("the requests don't share the load")
+		}
+	}
+
+	// Each request acquires a reference.
+	key := resourceKey{
+		resourceType: definitions.ResourceTypeAudio,
+		id:           "jump",
+	}
+
+	if refs := buffer.references(key); refs != requests {
+		t.Fatalf("wrong number of references: %d", refs)
+	}
+}
+
+func TestBufferDoesntKeepFailedLoads(t *testing.T) {
+	buffer := newResourceBuffer(DefaultMemoryBudget)
+
+	_, err := loadResource(buffer, buffer.audio,
+		definitions.ResourceTypeAudio, "jump",
+		func() ([]byte, error) {
+			return nil, fmt.Errorf("the resource file is broken")
+		}, audioSize)
+
+	if err == nil {
+		t.Fatal("no error for the failed load")
+	}
+
+	audio, err := loadTestAudio(buffer, "jump", "jump sound")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(audio) != "jump sound" {
+		t.Fatalf("wrong audio: %q", audio)
+	}
+
+	if _, ok := buffer.audio["jump"]; !ok {
+		t.Fatal("the loaded audio is not buffered")
+	}
+}
+
+func TestBufferEvictsUnusedResources(t *testing.T) {
+	// The budget fits two of the sounds.
+	buffer := newResourceBuffer(20)
+
+	for _, name := range []string{"jump", "shot", "step"} {
+		_, err := loadTestAudio(buffer, name, "0123456789")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The referenced resources
+	// are never evicted.
+	if buffer.memoryUsage() != 30 || len(buffer.audio) != 3 {
+		t.Fatalf("the referenced audio is evicted: %d bytes used",
+			buffer.memoryUsage())
+	}
+
+	for _, name := range []string{"step", "jump", "shot"} {
+		err := buffer.release(resourceKey{
+			resourceType: definitions.ResourceTypeAudio,
+			id:           name,
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The least recently released
+	// audio is evicted.
+	if _, ok := buffer.audio["step"]; ok {
+		t.Fatal("the least recently used audio is not evicted")
+	}
+
+	if buffer.memoryUsage() != 20 || len(buffer.audio) != 2 {
+		t.Fatalf("wrong memory usage: %d bytes", buffer.memoryUsage())
+	}
+
+	// The acquired audio is not evicted
+	// when the budget shrinks.
+	_, err := loadTestAudio(buffer, "jump", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer.setBudget(0)
+
+	if _, ok := buffer.audio["jump"]; !ok || len(buffer.audio) != 1 {
+		t.Fatal("wrong audio evicted")
+	}
+
+	err = buffer.release(resourceKey{
+		resourceType: definitions.ResourceTypeAudio,
+		id:           "jump",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buffer.memoryUsage() != 0 || len(buffer.audio) != 0 {
+		t.Fatal("the released audio is not evicted")
+	}
+
+	err = buffer.release(resourceKey{
+		resourceType: definitions.ResourceTypeAudio,
+		id:           "jump",
+	})
+
+	if err == nil {
+		t.Fatal("no error for the evicted audio")
+	}
+}
+
+func TestBufferBudgetCountsUnusedResources(t *testing.T) {
+	// The budget fits one of the sounds.
+	buffer := newResourceBuffer(10)
+
+	for _, name := range []string{"jump", "shot"} {
+		_, err := loadTestAudio(buffer, name, "0123456789")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := buffer.release(resourceKey{
+		resourceType: definitions.ResourceTypeAudio,
+		id:           "shot",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The referenced audio doesn't make
+	// the released one exceed the budget.
+	if _, ok := buffer.audio["shot"]; !ok || buffer.memoryUsage() != 20 {
+		t.Fatal("the unused audio fitting in the budget is evicted")
+	}
+}
+
+func TestBufferFindsResourcesByPointer(t *testing.T) {
+	buffer := newResourceBuffer(0)
+	font, err := loadResource(buffer, buffer.fonts,
+		definitions.ResourceTypeFont, "regular",
+		func() (*truetype.Font, error) {
+			return new(truetype.Font), nil
+		}, func(*truetype.Font) int64 {
+			return 10
+		})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resourceType, id, ok := buffer.findResourceID(font)
+
+	if !ok || resourceType != definitions.ResourceTypeFont || id != "regular" {
+		t.Fatalf("wrong resource: %s '%s'", resourceType, id)
+	}
+
+	if _, _, ok := buffer.findResourceID(new(truetype.Font)); ok {
+		t.Fatal("the font not loaded by the buffer is found")
+	}
+
+	err = buffer.release(resourceKey{
+		resourceType: definitions.ResourceTypeFont,
+		id:           "regular",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, ok := buffer.findResourceID(font); ok || len(buffer.ids) != 0 {
+		t.Fatal("the evicted font is still indexed")
+	}
+}
@@ -0,0 +1,52 @@
+package resources
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultMemoryBudget is the amount of memory
+	// the resources nothing references can occupy
+	// in the buffer of the loader if no other
+	// budget is specified.
+	DefaultMemoryBudget int64 = 256 << 20
+)
+
+// ResourceLoaderOption changes the way
+// the resource loader is created.
+type ResourceLoaderOption func(params *resourceLoaderParameters) error
+
+// resourceLoaderParameters holds the parameters
+// to be used for creation of the resource loader.
+type resourceLoaderParameters struct {
+	memoryBudget         int64
+	cacheProgramBinaries bool
+}
+
+// ResourceLoaderOptionWithMemoryBudget sets the amount
+// of memory in bytes the resources nothing references
+// can occupy before they're evicted from the buffer.
+// Zero budget makes the loader evict the resources
+// as soon as they're released.
+func ResourceLoaderOptionWithMemoryBudget(budget int64) ResourceLoaderOption {
+	return func(params *resourceLoaderParameters) error {
+		if budget < 0 {
+			return fmt.Errorf(
+				"wrong memory budget: '%d'", budget)
+		}
+
+		params.memoryBudget = budget
+		return nil
+	}
+}
+
+// ResourceLoaderOptionWithProgramBinaryCache makes
+// the loader store the binaries of the linked shader
+// programs in the resource file, so the programs are
+// loaded without compilation next time.
+func ResourceLoaderOptionWithProgramBinaryCache() ResourceLoaderOption {
+	return func(params *resourceLoaderParameters) error {
+		params.cacheProgramBinaries = true
+		return nil
+	}
+}
@@ -2,11 +2,14 @@ package resources
 
 import (
 	"bytes"
+	"encoding/gob"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/alacrity-engine/core/anim"
+	"github.com/alacrity-engine/core/definitions"
 	"github.com/alacrity-engine/core/render"
 	"github.com/golang/freetype/truetype"
 
@@ -14,19 +17,22 @@ import (
 	bolt "go.etcd.io/bbolt"
 )
 
-// TODO: load and cache shader sources,
-// and pre-compiled shaders.
-
-// TODO: add asynchronous versions of all
-// the resource loading functions to use
-// them to load prefabs in runtime.
-
 // ResourceLoader loads sprites,
 // animations, sound and text
 // from resource files.
+//
+// The resources which don't need OpenGL can
+// be loaded from any goroutine concurrently.
+//
+// Each load of a picture, a texture, a font, an
+// animation or an audio stream acquires a reference
+// to the resource. When the resource is not needed
+// anymore, it should be released, so the loader can
+// evict it from the memory.
 type ResourceLoader struct {
-	resourceFile *bolt.DB
-	buffer       *resourceBuffer
+	resourceFile         *bolt.DB
+	buffer               *resourceBuffer
+	cacheProgramBinaries bool
 }
 
 // Close closes the resource file.
@@ -34,52 +40,80 @@ func (loader *ResourceLoader) Close() error {
 	return loader.resourceFile.Close()
 }
 
-// LoadAnimation loads the animation with spritesheet
-// and frames from the resource file.
-func (loader *ResourceLoader) LoadAnimation(animID string) (*anim.Animation, error) {
-	// Load the animation frames from the buffer
-	// or the resource file.
-	animData, err := loader.buffer.takeAnimation(animID)
-
-	if err != nil {
-		switch err.(type) {
-		case *ErrorAnimationDoesntExist:
-			err = loader.resourceFile.View(func(tx *bolt.Tx) error {
-				buck := tx.Bucket([]byte("animations"))
+// Release removes the reference to the picture, the
+// texture, the font, the animation or the spritesheet
+// acquired by the load. The audio streams are released
+// when they're closed. When nothing references the
+// resource, it may be evicted from the memory, so
+// it must not be used after it's released. The
+// evicted textures are deleted from the GPU by
+// the next ProcessMainThreadQueue call.
+func (loader *ResourceLoader) Release(resource interface{}) error {
+	switch res := resource.(type) {
+	case *anim.Animation:
+		return loader.buffer.releaseAnimation(res)
+
+	case *Spritesheet:
+		return loader.buffer.release(resourceKey{
+			resourceType: definitions.ResourceTypeTexture,
+			id:           res.textureID,
+		})
+	}
 
-				if buck == nil {
-					return fmt.Errorf(
-						"the 'animations' bucket doesn't exist")
-				}
+	resourceType, id, ok := loader.buffer.findResourceID(resource)
 
-				data := buck.Get([]byte(animID))
+	if !ok {
+		return fmt.Errorf("the resource is not loaded by the loader")
+	}
 
-				if data == nil {
-					return fmt.Errorf("no '%s' animation", animID)
-				}
+	return loader.buffer.release(resourceKey{
+		resourceType: resourceType,
+		id:           id,
+	})
+}
 
-				animData, err = codec.AnimationDataFromBytes(data)
+// References returns the number of references to
+// the resource of the type acquired by the loads.
+func (loader *ResourceLoader) References(resourceType, id string) int {
+	return loader.buffer.references(resourceKey{
+		resourceType: resourceType,
+		id:           id,
+	})
+}
 
-				if err != nil {
-					return err
-				}
+// MemoryUsage returns the amount of memory in bytes
+// occupied by the pictures, the textures, the fonts
+// and the audio in the buffer of the loader.
+func (loader *ResourceLoader) MemoryUsage() int64 {
+	return loader.buffer.memoryUsage()
+}
 
-				return nil
-			})
+// SetMemoryBudget sets the amount of memory in bytes
+// the resources nothing references can occupy before
+// they're evicted from the buffer.
+func (loader *ResourceLoader) SetMemoryBudget(budget int64) error {
+	if budget < 0 {
+		return fmt.Errorf(
+			"wrong memory budget: '%d'", budget)
+	}
 
-			if err != nil {
-				return nil, err
-			}
+	loader.buffer.setBudget(budget)
 
-			err = loader.buffer.putAnimation(animID, animData)
+	return nil
+}
 
-			if err != nil {
-				return nil, err
-			}
+// LoadAnimation loads the animation with spritesheet
+// and frames from the resource file.
+//
+// Must be called from the main thread
+// as the texture may be uploaded to the GPU.
+func (loader *ResourceLoader) LoadAnimation(animID string) (*anim.Animation, error) {
+	// Load the animation frames from the buffer
+	// or the resource file.
+	animData, err := loader.loadAnimationData(animID)
 
-		default:
-			return nil, err
-		}
+	if err != nil {
+		return nil, err
 	}
 
 	texture, err := loader.LoadTexture(animData.TextureID)
@@ -88,6 +122,26 @@ func (loader *ResourceLoader) LoadAnimation(animID string) (*anim.Animation, err
 		return nil, err
 	}
 
+	return loader.newAnimation(animID, animData, texture)
+}
+
+// loadAnimationData loads the animation
+// data from the buffer or the resource file.
+func (loader *ResourceLoader) loadAnimationData(animID string) (*codec.AnimationData, error) {
+	return loadResource(loader.buffer, loader.buffer.animations,
+		definitions.ResourceTypeAnimation, animID,
+		func() (*codec.AnimationData, error) {
+			return loader.readAnimationData(animID)
+		}, nil)
+}
+
+// newAnimation creates the animation
+// with the data and the texture. If the
+// animation cannot be created, the reference
+// to the texture is released.
+func (loader *ResourceLoader) newAnimation(
+	animID string, animData *codec.AnimationData, texture *render.Texture,
+) (*anim.Animation, error) {
 	delays := []time.Duration{}
 
 	for _, duration := range animData.Durations {
@@ -95,268 +149,243 @@ func (loader *ResourceLoader) LoadAnimation(animID string) (*anim.Animation, err
 		delays = append(delays, delay)
 	}
 
-	anim, err := anim.NewAnimation(
+	animation, err := anim.NewAnimation(
 		texture, animData.Frames, delays, false)
 
 	if err != nil {
+		releaseErr := loader.Release(texture)
+
+		if releaseErr != nil {
+			return nil, fmt.Errorf("%w (cannot release the texture: %v)",
+				err, releaseErr)
+		}
+
 		return nil, err
 	}
 
-	return anim, nil
+	loader.buffer.putAnimationID(animation, animID)
+
+	return animation, nil
 }
 
+// LoadTexture loads the texture and its picture
+// from the resource file and uploads it to the GPU.
+//
+// Must be called from the main thread.
 func (loader *ResourceLoader) LoadTexture(name string) (*render.Texture, error) {
-	texture, err := loader.buffer.takeTexture(name)
-
-	if err != nil {
-		switch err.(type) {
-		case *ErrorTextureDoesntExist:
-			var texData *codec.TextureData
-
-			err = loader.resourceFile.View(func(tx *bolt.Tx) error {
-				buck := tx.Bucket([]byte("textures"))
-
-				if buck == nil {
-					return fmt.Errorf("bucket 'textures' not found")
-				}
-
-				data := buck.Get([]byte(name))
-
-				if data == nil {
-					return fmt.Errorf("no '%s' texture", name)
-				}
-
-				texData, err = codec.TextureDataFromBytes(data)
+	texture, ok := acquireResource(loader.buffer,
+		loader.buffer.textures, definitions.ResourceTypeTexture, name)
 
-				if err != nil {
-					return err
-				}
+	if ok {
+		return texture, nil
+	}
 
-				return nil
-			})
+	texData, err := loader.readTextureData(name)
 
-			if err != nil {
-				return nil, err
-			}
+	if err != nil {
+		return nil, err
+	}
 
-			pic, err := loader.LoadPicture(texData.PictureID)
+	pic, err := loader.LoadPicture(texData.PictureID)
 
-			if err != nil {
-				return nil, err
-			}
+	if err != nil {
+		return nil, err
+	}
 
-			texture = render.NewTextureFromPicture(
-				pic, render.TextureFiltering(texData.Filtering))
-			err = loader.buffer.putTexture(name, texture)
+	return loader.uploadTexture(name, texData, pic)
+}
 
-			if err != nil {
-				return nil, err
+// uploadTexture uploads the picture to the GPU and
+// puts the texture in the buffer. If the texture was
+// already uploaded, the buffered one is returned.
+// The reference to the picture acquired for the
+// upload is released, so its pixel data can
+// be evicted.
+//
+// Must be called from the main thread.
+func (loader *ResourceLoader) uploadTexture(name string, texData *codec.TextureData, pic *render.Picture) (*render.Texture, error) {
+	texture, ok := acquireResource(loader.buffer,
+		loader.buffer.textures, definitions.ResourceTypeTexture, name)
+
+	if !ok {
+		texture = render.NewTextureFromPicture(
+			pic, render.TextureFiltering(texData.Filtering))
+		// The texture occupies 4 bytes per pixel.
+		err := loader.buffer.putTexture(name, texture,
+			int64(pic.Width)*int64(pic.Height)*4)
+
+		if err != nil {
+			texture.Delete()
+			releaseErr := loader.releasePicture(texData.PictureID)
+
+			if releaseErr != nil {
+				return nil, fmt.Errorf("%w (cannot release the picture: %v)",
+					err, releaseErr)
 			}
 
-		default:
 			return nil, err
 		}
 	}
 
-	return texture, nil
-}
-
-func (loader *ResourceLoader) loadSpritesheet(id string) (*codec.SpritesheetData, error) {
-	ss, err := loader.buffer.takeSpritesheet(id)
+	err := loader.releasePicture(texData.PictureID)
 
 	if err != nil {
-		switch err.(type) {
-		case *ErrorSpritesheetDoesntExist:
-			err = loader.resourceFile.View(func(tx *bolt.Tx) error {
-				buck := tx.Bucket([]byte("spritesheets"))
-
-				if buck == nil {
-					return fmt.Errorf("no 'spritesheets' bucket found")
-				}
-
-				data := buck.Get([]byte(id))
-
-				if data == nil {
-					return fmt.Errorf("no '%s' spritesheet found", id)
-				}
-
-				ss, err = codec.SpritesheetDataFromBytes(data)
-
-				if err != nil {
-					return err
-				}
-
-				return nil
-			})
-
-			if err != nil {
-				return nil, err
-			}
-
-			err = loader.buffer.putSpritesheet(id, ss)
-
-			if err != nil {
-				return nil, err
-			}
-
-		default:
-			return nil, err
-		}
+		return nil, err
 	}
 
-	return ss, nil
+	return texture, nil
+}
+
+// releasePicture releases the reference
+// to the picture acquired for the upload.
+func (loader *ResourceLoader) releasePicture(pictureID string) error {
+	return loader.buffer.release(resourceKey{
+		resourceType: definitions.ResourceTypePicture,
+		id:           pictureID,
+	})
 }
 
 // LoadPicture loads the picture from the resource file by the name of the picture.
+//
+// The picture is stored in the 'spritesheets' bucket
+// under its name as a compressed codec picture.
 func (loader *ResourceLoader) LoadPicture(name string) (*render.Picture, error) {
-	picture, err := loader.buffer.takePicture(name)
-
-	if err != nil {
-		switch err.(type) {
-		case *ErrorPictureDoesntExist:
-			er := loader.resourceFile.View(func(tx *bolt.Tx) error {
-				buck := tx.Bucket([]byte("spritesheets"))
-
-				if buck == nil {
-					return fmt.Errorf("bucket 'spritesheets' not found")
-				}
-
-				pictureBytes := buck.Get([]byte(name))
-
-				if pictureBytes == nil {
-					return fmt.Errorf("picture with ID '%s' not found",
-						name)
-				}
-
-				compressedPicture, err := codec.CompressedPictureFromBytes(pictureBytes)
-
-				if err != nil {
-					return err
-				}
-
-				pictureDataDec, err := compressedPicture.Decompress()
-
-				if err != nil {
-					return err
-				}
-
-				picture = PictureDataToPicture(pictureDataDec)
-
-				return nil
-			})
-
-			if er != nil {
-				return nil, er
-			}
-
-			er = loader.buffer.putPicture(name, picture)
-
-			if er != nil {
-				return nil, er
-			}
-
-		default:
-			return nil, err
-		}
-	}
-
-	return picture, nil
+	return loadResource(loader.buffer, loader.buffer.pictures,
+		definitions.ResourceTypePicture, name,
+		func() (*render.Picture, error) {
+			return loader.readPicture(name)
+		}, pictureSize)
 }
 
 // LoadFont loads a font stored in the resource file under the specified name.
 func (loader *ResourceLoader) LoadFont(name string) (*truetype.Font, error) {
-	font, err := loader.buffer.takeFont(name)
+	var size int64
+
+	return loadResource(loader.buffer, loader.buffer.fonts,
+		definitions.ResourceTypeFont, name,
+		func() (*truetype.Font, error) {
+			font, fontSize, err := loader.readFont(name)
+			size = int64(fontSize)
+
+			return font, err
+		}, func(*truetype.Font) int64 {
+			return size
+		})
+}
+
+// LoadAudio loads the specified audio from the resource file.
+// The audio is released when the stream is closed.
+func (loader *ResourceLoader) LoadAudio(name string) (io.ReadCloser, error) {
+	audio, err := loadResource(loader.buffer, loader.buffer.audio,
+		definitions.ResourceTypeAudio, name,
+		func() ([]byte, error) {
+			return loader.readAudio(name)
+		}, func(audio []byte) int64 {
+			return int64(len(audio))
+		})
 
 	if err != nil {
-		switch err.(type) {
-		case *ErrorFontDoesntExist:
-			er := loader.resourceFile.View(func(tx *bolt.Tx) error {
-				buck := tx.Bucket([]byte("fonts"))
+		return nil, err
+	}
 
-				if buck == nil {
-					return fmt.Errorf("bucket 'fonts' not found")
-				}
+	return loader.newAudioStream(name, audio), nil
+}
 
-				fontData := buck.Get([]byte(name))
+// audioStream reads the buffered audio and
+// releases it when the stream is closed.
+type audioStream struct {
+	*bytes.Reader
+	release func() error
+	once    *sync.Once
+}
 
-				if fontData == nil {
-					return fmt.Errorf("font '%s' not found", name)
-				}
+// Close releases the audio.
+func (stream *audioStream) Close() error {
+	var err error
 
-				var err error
-				font, err = truetype.Parse(fontData)
+	stream.once.Do(func() {
+		err = stream.release()
+	})
 
-				if err != nil {
-					return err
-				}
+	return err
+}
 
-				return nil
+// newAudioStream returns a new stream to read the
+// audio the caller has acquired a reference to.
+func (loader *ResourceLoader) newAudioStream(name string, audio []byte) io.ReadCloser {
+	return &audioStream{
+		Reader: bytes.NewReader(audio),
+		release: func() error {
+			return loader.buffer.release(resourceKey{
+				resourceType: definitions.ResourceTypeAudio,
+				id:           name,
 			})
+		},
+		once: new(sync.Once),
+	}
+}
 
-			if er != nil {
-				return nil, er
-			}
+// pictureSize returns the amount of
+// memory occupied by the picture.
+func pictureSize(picture *render.Picture) int64 {
+	return int64(len(picture.Pix))
+}
 
-			er = loader.buffer.putFont(name, font)
+// LoadSceneDefinition loads the gob-encoded
+// scene definition from the resource file.
+//
+// Scene definitions are not buffered as
+// each scene is usually loaded only once.
+func (loader *ResourceLoader) LoadSceneDefinition(id string) (*definitions.SceneDefinition, error) {
+	var sceneDef definitions.SceneDefinition
 
-			if er != nil {
-				return nil, er
-			}
+	err := loader.resourceFile.View(func(tx *bolt.Tx) error {
+		buck := tx.Bucket([]byte("scenes"))
 
-		default:
-			return nil, err
+		if buck == nil {
+			return fmt.Errorf("bucket 'scenes' not found")
 		}
-	}
-
-	return font, nil
-}
 
-// LoadAudio loads the specified audio from the resource file.
-func (loader *ResourceLoader) LoadAudio(name string) (io.ReadCloser, error) {
-	audio, err := loader.buffer.takeAudio(name)
+		data := buck.Get([]byte(id))
 
-	if err != nil {
-		switch err.(type) {
-		case *ErrorAudioDoesntExist:
-			er := loader.resourceFile.View(func(tx *bolt.Tx) error {
-				buck := tx.Bucket([]byte("audio"))
-
-				if buck == nil {
-					return fmt.Errorf("bucket 'audio' not found")
-				}
+		if data == nil {
+			return fmt.Errorf("scene '%s' not found", id)
+		}
 
-				audio = buck.Get([]byte(name))
+		return gob.NewDecoder(bytes.NewReader(data)).Decode(&sceneDef)
+	})
 
-				if audio == nil {
-					return fmt.Errorf("audio '%s' not found", name)
-				}
+	if err != nil {
+		return nil, err
+	}
 
-				return nil
-			})
+	return &sceneDef, nil
+}
 
-			if er != nil {
-				return nil, er
-			}
+// FindResourceID returns the type and the ID
+// of the resource previously loaded by the loader.
+//
+// Textures, pictures, fonts, animations, spritesheets,
+// shaders and shader programs can be looked up.
+func (loader *ResourceLoader) FindResourceID(resource interface{}) (string, string, bool) {
+	return loader.buffer.findResourceID(resource)
+}
 
-			er = loader.buffer.putAudio(name, audio)
+// NewResourceLoader crates a new resource loader for the specified resource file.
+func NewResourceLoader(file string, options ...ResourceLoaderOption) (*ResourceLoader, error) {
+	params := resourceLoaderParameters{
+		memoryBudget: DefaultMemoryBudget,
+	}
 
-			if er != nil {
-				return nil, er
-			}
+	for i := 0; i < len(options); i++ {
+		option := options[i]
+		err := option(&params)
 
-		default:
+		if err != nil {
 			return nil, err
 		}
 	}
 
-	reader := bytes.NewReader(audio)
-	stream := io.NopCloser(reader)
-
-	return stream, nil
-}
-
-// NewResourceLoader crates a new resource loader for the specified resource file.
-func NewResourceLoader(file string) (*ResourceLoader, error) {
 	resourceFile, err := bolt.Open(file, 0666, nil)
 
 	if err != nil {
@@ -364,7 +393,8 @@ func NewResourceLoader(file string) (*ResourceLoader, error) {
 	}
 
 	return &ResourceLoader{
-		resourceFile: resourceFile,
-		buffer:       newResourceBuffer(),
+		resourceFile:         resourceFile,
+		buffer:               newResourceBuffer(params.memoryBudget),
+		cacheProgramBinaries: params.cacheProgramBinaries,
 	}, nil
 }
@@ -0,0 +1,52 @@
+package resources
+
+import "sync"
+
+var (
+	// mainThreadQueue is the work queued by the
+	// worker goroutines which must be done on
+	// the main thread, e.g. OpenGL calls.
+	mainThreadQueue []func()
+	// mainThreadQueueLocker guards
+	// the main thread work queue.
+	mainThreadQueueLocker *sync.Mutex
+)
+
+func init() {
+	mainThreadQueue = []func(){}
+	mainThreadQueueLocker = new(sync.Mutex)
+}
+
+// queueMainThreadWork queues the work to be
+// done by the next ProcessMainThreadQueue call.
+//
+// Can be called from any goroutine.
+func queueMainThreadWork(work func()) {
+	mainThreadQueueLocker.Lock()
+	defer mainThreadQueueLocker.Unlock()
+
+	mainThreadQueue = append(mainThreadQueue, work)
+}
+
+// ProcessMainThreadQueue does all the work queued by
+// the asynchronous resource loaders, i.e. uploads the
+// decoded resources to the GPU and completes the
+// loading processes, and deletes the textures evicted
+// from the resource buffers. The work queued during
+// the call is done on the next call.
+//
+// Must be called from the main thread on every frame,
+// otherwise the evicted textures stay on the GPU.
+// engine.Loop calls it at the beginning of each frame,
+// the games running their own loops must call it
+// themselves.
+func ProcessMainThreadQueue() {
+	mainThreadQueueLocker.Lock()
+	queue := mainThreadQueue
+	mainThreadQueue = []func(){}
+	mainThreadQueueLocker.Unlock()
+
+	for _, work := range queue {
+		work()
+	}
+}
@@ -0,0 +1,274 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/render"
+	"github.com/golang/freetype/truetype"
+
+	codec "github.com/alacrity-engine/resource-codec"
+	bolt "go.etcd.io/bbolt"
+)
+
+// The functions below read and decode the
+// resources from the resource file without
+// touching the resource buffer, so they can
+// be called from the worker goroutines.
+
+// readAnimationData reads the animation
+// data from the resource file.
+func (loader *ResourceLoader) readAnimationData(animID string) (*codec.AnimationData, error) {
+	var animData *codec.AnimationData
+
+	err := loader.resourceFile.View(func(tx *bolt.Tx) error {
+		buck := tx.Bucket([]byte("animations"))
+
+		if buck == nil {
+			return fmt.Errorf(
+				"the 'animations' bucket doesn't exist")
+		}
+
+		data := buck.Get([]byte(animID))
+
+		if data == nil {
+			return fmt.Errorf("no '%s' animation", animID)
+		}
+
+		var err error
+		animData, err = codec.AnimationDataFromBytes(data)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return animData, nil
+}
+
+// readTextureData reads the texture
+// data from the resource file.
+func (loader *ResourceLoader) readTextureData(name string) (*codec.TextureData, error) {
+	var texData *codec.TextureData
+
+	err := loader.resourceFile.View(func(tx *bolt.Tx) error {
+		buck := tx.Bucket([]byte("textures"))
+
+		if buck == nil {
+			return fmt.Errorf("bucket 'textures' not found")
+		}
+
+		data := buck.Get([]byte(name))
+
+		if data == nil {
+			return fmt.Errorf("no '%s' texture", name)
+		}
+
+		var err error
+		texData, err = codec.TextureDataFromBytes(data)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return texData, nil
+}
+
+// readSpritesheet reads the spritesheet definition
+// from the resource file. If there is no definition,
+// the spritesheet is read from the codec spritesheet
+// data.
+func (loader *ResourceLoader) readSpritesheet(id string) (*definitions.SpritesheetDefinition, error) {
+	var def *definitions.SpritesheetDefinition
+
+	err := loader.resourceFile.View(func(tx *bolt.Tx) error {
+		if buck := tx.Bucket([]byte("spritesheet-definitions")); buck != nil {
+			if data := buck.Get([]byte(id)); data != nil {
+				def = &definitions.SpritesheetDefinition{}
+				return gob.NewDecoder(bytes.NewReader(data)).Decode(def)
+			}
+		}
+
+		buck := tx.Bucket([]byte("spritesheets"))
+
+		if buck == nil {
+			return fmt.Errorf("no 'spritesheets' bucket found")
+		}
+
+		data := buck.Get([]byte(id))
+
+		if data == nil {
+			return fmt.Errorf("no '%s' spritesheet found", id)
+		}
+
+		ssData, err := codec.SpritesheetDataFromBytes(data)
+
+		if err != nil {
+			return err
+		}
+
+		def = spritesheetDefinitionFromData(id, ssData)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return def, nil
+}
+
+// readPicture reads the compressed picture
+// from the resource file and decompresses it.
+func (loader *ResourceLoader) readPicture(name string) (*render.Picture, error) {
+	var picture *render.Picture
+
+	err := loader.resourceFile.View(func(tx *bolt.Tx) error {
+		buck := tx.Bucket([]byte("spritesheets"))
+
+		if buck == nil {
+			return fmt.Errorf("bucket 'spritesheets' not found")
+		}
+
+		pictureBytes := buck.Get([]byte(name))
+
+		if pictureBytes == nil {
+			return fmt.Errorf("picture with ID '%s' not found",
+				name)
+		}
+
+		compressedPicture, err := codec.CompressedPictureFromBytes(pictureBytes)
+
+		if err != nil {
+			return err
+		}
+
+		pictureDataDec, err := compressedPicture.Decompress()
+
+		if err != nil {
+			return err
+		}
+
+		picture = PictureDataToPicture(pictureDataDec)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return picture, nil
+}
+
+// readFont reads the font from the resource
+// file and parses it. The size of the font
+// data is returned along with the font.
+func (loader *ResourceLoader) readFont(name string) (*truetype.Font, int, error) {
+	var font *truetype.Font
+	var size int
+
+	err := loader.resourceFile.View(func(tx *bolt.Tx) error {
+		buck := tx.Bucket([]byte("fonts"))
+
+		if buck == nil {
+			return fmt.Errorf("bucket 'fonts' not found")
+		}
+
+		fontData := buck.Get([]byte(name))
+
+		if fontData == nil {
+			return fmt.Errorf("font '%s' not found", name)
+		}
+
+		// The font keeps the data, and the data returned
+		// by the bucket is only valid during the transaction.
+		data := make([]byte, len(fontData))
+		copy(data, fontData)
+
+		var err error
+		font, err = truetype.Parse(data)
+		size = len(data)
+
+		if err != nil {
+			return fmt.Errorf("cannot parse font '%s': %w", name, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return font, size, nil
+}
+
+// readAudio reads the audio data
+// from the resource file.
+func (loader *ResourceLoader) readAudio(name string) ([]byte, error) {
+	var audio []byte
+
+	err := loader.resourceFile.View(func(tx *bolt.Tx) error {
+		buck := tx.Bucket([]byte("audio"))
+
+		if buck == nil {
+			return fmt.Errorf("bucket 'audio' not found")
+		}
+
+		data := buck.Get([]byte(name))
+
+		if data == nil {
+			return fmt.Errorf("audio '%s' not found", name)
+		}
+
+		// The data returned by the bucket is only
+		// valid during the transaction.
+		audio = make([]byte, len(data))
+		copy(audio, data)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return audio, nil
+}
+
+// readGob reads the gob-encoded value
+// from the bucket of the resource file.
+func (loader *ResourceLoader) readGob(bucket, id string, value interface{}) error {
+	return loader.resourceFile.View(func(tx *bolt.Tx) error {
+		buck := tx.Bucket([]byte(bucket))
+
+		if buck == nil {
+			return fmt.Errorf("bucket '%s' not found", bucket)
+		}
+
+		data := buck.Get([]byte(id))
+
+		if data == nil {
+			return fmt.Errorf("no '%s' in bucket '%s'", id, bucket)
+		}
+
+		return gob.NewDecoder(bytes.NewReader(data)).Decode(value)
+	})
+}
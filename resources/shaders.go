@@ -0,0 +1,218 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/render"
+	"github.com/alacrity-engine/core/system"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// programBinary is the pre-compiled shader
+// program stored in the resource file. The binary
+// can only be loaded by the driver it was built by,
+// so it's stored along with the renderer, the OpenGL
+// version and the hash of the shader sources.
+type programBinary struct {
+	Renderer   string
+	Version    string
+	SourceHash uint64
+	Format     uint32
+	Data       []byte
+}
+
+// matches returns true if the binary was built
+// by the renderer of the version out of the
+// shader sources with the hash.
+func (binary programBinary) matches(renderer, version string, sourceHash uint64) bool {
+	return len(binary.Data) > 0 && binary.Renderer == renderer &&
+		binary.Version == version && binary.SourceHash == sourceHash
+}
+
+// LoadShader loads the GLSL source of the
+// shader from the resource file and compiles it.
+//
+// The shader is stored in the 'shaders' bucket under
+// its ID as a gob-encoded definitions.ShaderDefinition
+// which holds the type and the GLSL source of the shader.
+//
+// Must be called from the main thread.
+func (loader *ResourceLoader) LoadShader(id string) (*render.Shader, error) {
+	return loadResource(loader.buffer, loader.buffer.shaders,
+		definitions.ResourceTypeShader, id,
+		func() (*render.Shader, error) {
+			shaderDef, err := loader.readShaderDefinition(id)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return render.NewShaderFromSource(shaderDef.Source, shaderDef.Type)
+		}, nil)
+}
+
+// readShaderDefinition reads the
+// shader definition from the resource file.
+func (loader *ResourceLoader) readShaderDefinition(id string) (*definitions.ShaderDefinition, error) {
+	var shaderDef definitions.ShaderDefinition
+	err := loader.readGob("shaders", id, &shaderDef)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &shaderDef, nil
+}
+
+// LoadShaderProgram loads the shader program from
+// the resource file. If the program binary was cached
+// by the same driver out of the same shader sources,
+// the program is loaded from the binary. Otherwise its
+// shaders are loaded and linked.
+//
+// The program is stored in the 'shader-programs' bucket
+// under its ID as a gob-encoded definitions.ShaderProgramDefinition
+// which holds the IDs of the vertex and the fragment shaders
+// from the 'shaders' bucket. The cached binaries are stored
+// in the 'shader-program-binaries' bucket under the
+// same IDs.
+//
+// Must be called from the main thread.
+func (loader *ResourceLoader) LoadShaderProgram(id string) (*render.ShaderProgram, error) {
+	return loadResource(loader.buffer, loader.buffer.shaderPrograms,
+		definitions.ResourceTypeShaderProgram, id,
+		func() (*render.ShaderProgram, error) {
+			return loader.linkShaderProgram(id)
+		}, nil)
+}
+
+// readShaderProgramDefinition reads the shader
+// program definition from the resource file and
+// returns it along with the hash of the sources
+// of its shaders.
+func (loader *ResourceLoader) readShaderProgramDefinition(id string) (*definitions.ShaderProgramDefinition, uint64, error) {
+	var programDef definitions.ShaderProgramDefinition
+	err := loader.readGob("shader-programs", id, &programDef)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	h := fnv.New64a()
+
+	for _, shaderID := range []string{
+		programDef.VertexShaderID,
+		programDef.FragmentShaderID,
+	} {
+		shaderDef, err := loader.readShaderDefinition(shaderID)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		gob.NewEncoder(h).Encode(shaderDef)
+	}
+
+	return &programDef, h.Sum64(), nil
+}
+
+// linkShaderProgram creates the shader program
+// from the cached binary or the shaders.
+func (loader *ResourceLoader) linkShaderProgram(id string) (*render.ShaderProgram, error) {
+	programDef, sourceHash, err := loader.readShaderProgramDefinition(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	renderer := system.Renderer()
+	version := system.Version()
+
+	var binary programBinary
+	err = loader.readGob("shader-program-binaries", id, &binary)
+
+	if err == nil && binary.matches(renderer, version, sourceHash) {
+		program, err := render.NewShaderProgramFromBinary(
+			binary.Format, binary.Data)
+
+		if err == nil {
+			return program, nil
+		}
+
+		// The binary is rejected by
+		// the driver, so the program is
+		// linked from the shaders again.
+	}
+
+	vertexShader, err := loader.LoadShader(programDef.VertexShaderID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fragmentShader, err := loader.LoadShader(programDef.FragmentShaderID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := render.NewShaderProgramFromShaders(
+		vertexShader, fragmentShader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if loader.cacheProgramBinaries {
+		// The program works without the cache, so
+		// the error is ignored and the program is
+		// linked from the shaders next time, e.g.
+		// when the driver doesn't support program
+		// binaries or the resource file is read-only.
+		_ = loader.writeProgramBinary(id, program, programBinary{
+			Renderer:   renderer,
+			Version:    version,
+			SourceHash: sourceHash,
+		})
+	}
+
+	return program, nil
+}
+
+// writeProgramBinary stores the binary of the linked
+// program in the resource file along with the key of
+// the binary to load the program from it next time.
+func (loader *ResourceLoader) writeProgramBinary(id string, program *render.ShaderProgram, binary programBinary) error {
+	format, data, err := program.Binary()
+
+	if err != nil {
+		return fmt.Errorf("cannot get the binary of shader program '%s': %w",
+			id, err)
+	}
+
+	binary.Format = format
+	binary.Data = data
+
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(binary)
+
+	if err != nil {
+		return err
+	}
+
+	return loader.resourceFile.Update(func(tx *bolt.Tx) error {
+		buck, err := tx.CreateBucketIfNotExists(
+			[]byte("shader-program-binaries"))
+
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(id), buf.Bytes())
+	})
+}
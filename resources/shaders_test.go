@@ -0,0 +1,144 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/render"
+	bolt "go.etcd.io/bbolt"
+)
+
+func gobBytes(t *testing.T, value interface{}) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(value)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func putTestResource(t *testing.T, loader *ResourceLoader, bucket, id string, data []byte) {
+	t.Helper()
+
+	err := loader.resourceFile.Update(func(tx *bolt.Tx) error {
+		buck, err := tx.CreateBucketIfNotExists([]byte(bucket))
+
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(id), data)
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShaderDefinitions(t *testing.T) {
+	loader := newTestResourceLoader(t, "shaders", map[string][]byte{
+		"vertex": gobBytes(t, definitions.ShaderDefinition{
+			Type:   render.ShaderTypeVertex,
+			Source: "void main() {}",
+		}),
+		"fragment": gobBytes(t, definitions.ShaderDefinition{
+			Type:   render.ShaderTypeFragment,
+			Source: "void main() {}",
+		}),
+	})
+	putTestResource(t, loader, "shader-programs", "sprite",
+		gobBytes(t, definitions.ShaderProgramDefinition{
+			VertexShaderID:   "vertex",
+			FragmentShaderID: "fragment",
+		}))
+	putTestResource(t, loader, "shader-programs", "broken",
+		gobBytes(t, definitions.ShaderProgramDefinition{
+			VertexShaderID:   "vertex",
+			FragmentShaderID: "missing",
+		}))
+
+	shaderDef, err := loader.readShaderDefinition("fragment")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if shaderDef.Type != render.ShaderTypeFragment || shaderDef.Source != "void main() {}" {
+		t.Fatalf("wrong shader definition: %v", shaderDef)
+	}
+
+	if _, err := loader.readShaderDefinition("missing"); err == nil {
+		t.Fatal("no error for the absent shader")
+	}
+
+	programDef, hash, err := loader.readShaderProgramDefinition("sprite")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if programDef.VertexShaderID != "vertex" || programDef.FragmentShaderID != "fragment" {
+		t.Fatalf("wrong shader program definition: %v", programDef)
+	}
+
+	if _, _, err := loader.readShaderProgramDefinition("broken"); err == nil {
+		t.Fatal("no error for the program with the absent shader")
+	}
+
+	// Changing the shader source
+	// changes the key of the binary.
+	putTestResource(t, loader, "shaders", "fragment",
+		gobBytes(t, definitions.ShaderDefinition{
+			Type:   render.ShaderTypeFragment,
+			Source: "void main() { discard; }",
+		}))
+
+	_, changedHash, err := loader.readShaderProgramDefinition("sprite")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changedHash == hash {
+		t.Fatal("the source hash doesn't change with the source")
+	}
+}
+
+func TestProgramBinaryMatches(t *testing.T) {
+	binary := programBinary{
+		Renderer:   "renderer",
+		Version:    "4.6",
+		SourceHash: 1,
+		Format:     1,
+		Data:       []byte{1},
+	}
+
+	if !binary.matches("renderer", "4.6", 1) {
+		t.Fatal("the binary doesn't match its own key")
+	}
+
+	for _, key := range []struct {
+		renderer, version string
+		hash              uint64
+	}{
+		{"other", "4.6", 1},
+		{"renderer", "4.5", 1},
+		{"renderer", "4.6", 2},
+	} {
+		if binary.matches(key.renderer, key.version, key.hash) {
+			t.Fatalf("the binary matches key %v", key)
+		}
+	}
+
+	// The binaries cached before the
+	// key was added match nothing.
+	if (programBinary{Format: 1, Data: []byte{1}}).matches("renderer", "4.6", 1) {
+		t.Fatal("the binary without the key matches")
+	}
+}
@@ -0,0 +1,232 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/math/geometry"
+	"github.com/alacrity-engine/core/render"
+
+	codec "github.com/alacrity-engine/resource-codec"
+)
+
+// Spritesheet is a texture divided into frames.
+// The frames can be addressed by their indices
+// or names, e.g. "player_idle_3".
+type Spritesheet struct {
+	id        string
+	textureID string
+	texture   *render.Texture
+	frames    []definitions.SpritesheetFrameDefinition
+	indices   map[string]int
+}
+
+// ID returns the ID of the spritesheet
+// in the resource file.
+func (ss *Spritesheet) ID() string {
+	return ss.id
+}
+
+// Texture returns the texture
+// of the spritesheet.
+func (ss *Spritesheet) Texture() *render.Texture {
+	return ss.texture
+}
+
+// FrameCount returns the number
+// of frames in the spritesheet.
+func (ss *Spritesheet) FrameCount() int {
+	return len(ss.frames)
+}
+
+// Frame returns the area of
+// the texture the frame occupies.
+func (ss *Spritesheet) Frame(index int) (geometry.Rect, error) {
+	if index < 0 || index >= len(ss.frames) {
+		return geometry.Rect{}, fmt.Errorf(
+			"spritesheet '%s' has no frame at index %d", ss.id, index)
+	}
+
+	return ss.frames[index].Area, nil
+}
+
+// FrameByName returns the area of the
+// texture the named frame occupies.
+func (ss *Spritesheet) FrameByName(name string) (geometry.Rect, error) {
+	index, ok := ss.FrameIndex(name)
+
+	if !ok {
+		return geometry.Rect{}, fmt.Errorf(
+			"spritesheet '%s' has no frame '%s'", ss.id, name)
+	}
+
+	return ss.frames[index].Area, nil
+}
+
+// FrameIndex returns the index of the named frame.
+func (ss *Spritesheet) FrameIndex(name string) (int, bool) {
+	index, ok := ss.indices[name]
+	return index, ok
+}
+
+// FrameName returns the name of the frame at the
+// index. The frames can be left unnamed.
+func (ss *Spritesheet) FrameName(index int) (string, error) {
+	if index < 0 || index >= len(ss.frames) {
+		return "", fmt.Errorf(
+			"spritesheet '%s' has no frame at index %d", ss.id, index)
+	}
+
+	return ss.frames[index].Name, nil
+}
+
+// NewFrameSprite creates a new sprite which draws the named
+// frame of the spritesheet with the shader program. The frame
+// of the sprite can be changed with SetSpriteFrame, so the
+// texture coordinates are usually drawn in the dynamic mode.
+//
+// Must be called from the main thread.
+func (ss *Spritesheet) NewFrameSprite(
+	vertexDrawMode, textureDrawMode, colorDrawMode render.DrawMode,
+	program *render.ShaderProgram, name string,
+) (*render.Sprite, error) {
+	if program == nil {
+		return nil, fmt.Errorf("the shader program is nil")
+	}
+
+	area, err := ss.FrameByName(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return render.NewSpriteFromTextureAndProgram(
+		vertexDrawMode, textureDrawMode, colorDrawMode,
+		ss.texture, program, area)
+}
+
+// SetSpriteFrame makes the sprite of the
+// spritesheet texture draw the named frame.
+func (ss *Spritesheet) SetSpriteFrame(sprite *render.Sprite, name string) error {
+	if sprite == nil {
+		return fmt.Errorf("the sprite is nil")
+	}
+
+	if sprite.Texture() != ss.texture {
+		return fmt.Errorf(
+			"the sprite doesn't use the texture of spritesheet '%s'", ss.id)
+	}
+
+	area, err := ss.FrameByName(name)
+
+	if err != nil {
+		return err
+	}
+
+	return sprite.SetTargetArea(area)
+}
+
+// newSpritesheet creates a new spritesheet
+// with the frames of the definition.
+func newSpritesheet(id string, def *definitions.SpritesheetDefinition) (*Spritesheet, error) {
+	ss := &Spritesheet{
+		id:        id,
+		textureID: def.TextureID,
+		frames:    make([]definitions.SpritesheetFrameDefinition, len(def.Frames)),
+		indices:   make(map[string]int, len(def.Frames)),
+	}
+
+	copy(ss.frames, def.Frames)
+
+	for i, frame := range ss.frames {
+		if frame.Name == "" {
+			continue
+		}
+
+		if _, ok := ss.indices[frame.Name]; ok {
+			return nil, fmt.Errorf(
+				"spritesheet '%s' has more than one frame '%s'",
+				id, frame.Name)
+		}
+
+		ss.indices[frame.Name] = i
+	}
+
+	return ss, nil
+}
+
+// spritesheetDefinitionFromData converts the codec
+// spritesheet data to the spritesheet definition. The
+// data is named after the texture of the spritesheet,
+// and the frames are named after the spritesheet and
+// their indices, e.g. "player_idle_3".
+func spritesheetDefinitionFromData(id string, ssData *codec.SpritesheetData) *definitions.SpritesheetDefinition {
+	def := &definitions.SpritesheetDefinition{
+		TextureID: ssData.Name,
+		Frames:    make([]definitions.SpritesheetFrameDefinition, len(ssData.Frames)),
+	}
+
+	for i, area := range ssData.Frames {
+		def.Frames[i] = definitions.SpritesheetFrameDefinition{
+			Name: fmt.Sprintf("%s_%d", id, i),
+			Area: area,
+		}
+	}
+
+	return def
+}
+
+// LoadSpritesheet loads the spritesheet definition
+// from the resource file along with its texture.
+// The texture is released when the spritesheet
+// is released.
+//
+// The spritesheet is stored in the 'spritesheet-definitions'
+// bucket under its ID as a gob-encoded definitions.SpritesheetDefinition
+// which holds the ID of the texture from the 'textures' bucket
+// and the frames. Each frame is the area of the texture
+// in pixels with an optional name unique within the
+// spritesheet. If there is no definition, the spritesheet
+// is read from the codec spritesheet data stored in the
+// 'spritesheets' bucket under the same ID.
+//
+// Must be called from the main thread.
+func (loader *ResourceLoader) LoadSpritesheet(id string) (*Spritesheet, error) {
+	def, err := loadResource(loader.buffer, loader.buffer.spritesheets,
+		definitions.ResourceTypeSpritesheet, id,
+		func() (*definitions.SpritesheetDefinition, error) {
+			return loader.readSpritesheet(id)
+		}, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ss, err := newSpritesheet(id, def)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ss.texture, err = loader.LoadTexture(def.TextureID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+// FindSpritesheetFrame returns the ID of the loaded
+// spritesheet of the texture and the name of its frame
+// occupying the area of the texture. Unnamed frames
+// are never found.
+func (loader *ResourceLoader) FindSpritesheetFrame(texture *render.Texture, area geometry.Rect) (string, string, bool) {
+	_, textureID, found := loader.buffer.findResourceID(texture)
+
+	if !found {
+		return "", "", false
+	}
+
+	return loader.buffer.findSpritesheetFrame(textureID, area)
+}
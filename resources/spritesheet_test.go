@@ -0,0 +1,187 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alacrity-engine/core/definitions"
+	"github.com/alacrity-engine/core/math/geometry"
+	"github.com/alacrity-engine/core/render"
+
+	codec "github.com/alacrity-engine/resource-codec"
+)
+
+func TestSpritesheetFrames(t *testing.T) {
+	ss, err := newSpritesheet("player", &definitions.SpritesheetDefinition{
+		TextureID: "player",
+		Frames: []definitions.SpritesheetFrameDefinition{
+			{Name: "player_idle_0", Area: geometry.R(0, 0, 16, 16)},
+			{Area: geometry.R(16, 0, 32, 16)},
+			{Name: "player_idle_2", Area: geometry.R(32, 0, 48, 16)},
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ss.FrameCount() != 3 {
+		t.Fatalf("wrong frame count: %d", ss.FrameCount())
+	}
+
+	area, err := ss.FrameByName("player_idle_2")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if area != geometry.R(32, 0, 48, 16) {
+		t.Fatalf("wrong frame area: %v", area)
+	}
+
+	if index, ok := ss.FrameIndex("player_idle_2"); !ok || index != 2 {
+		t.Fatalf("wrong frame index: %d", index)
+	}
+
+	area, err = ss.Frame(1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if area != geometry.R(16, 0, 32, 16) {
+		t.Fatalf("wrong frame area: %v", area)
+	}
+
+	name, err := ss.FrameName(0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name != "player_idle_0" {
+		t.Fatalf("wrong frame name: %s", name)
+	}
+
+	if _, err := ss.FrameByName("player_run_0"); err == nil {
+		t.Fatal("no error for the missing frame")
+	}
+
+	if _, err := ss.Frame(3); err == nil {
+		t.Fatal("no error for the frame out of range")
+	}
+
+	_, err = newSpritesheet("enemy", &definitions.SpritesheetDefinition{
+		Frames: []definitions.SpritesheetFrameDefinition{
+			{Name: "enemy_idle_0"},
+			{Name: "enemy_idle_0"},
+		},
+	})
+
+	if err == nil {
+		t.Fatal("no error for the duplicate frame names")
+	}
+}
+
+func TestLoadSpritesheet(t *testing.T) {
+	loader := newTestResourceLoader(t, "spritesheet-definitions", map[string][]byte{
+		"player": gobBytes(t, definitions.SpritesheetDefinition{
+			TextureID: "player",
+			Frames: []definitions.SpritesheetFrameDefinition{
+				{Name: "player_idle_0", Area: geometry.R(0, 0, 16, 16)},
+				{Name: "player_idle_1", Area: geometry.R(16, 0, 32, 16)},
+			},
+		}),
+	})
+
+	// The texture is buffered beforehand
+	// not to upload it to the GPU.
+	texture := &render.Texture{}
+	err := loader.buffer.putTexture("player", texture, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := loader.LoadSpritesheet("player")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ss.ID() != "player" || ss.Texture() != texture {
+		t.Fatal("the spritesheet is not loaded along with its texture")
+	}
+
+	if refs := loader.References(definitions.ResourceTypeTexture, "player"); refs != 2 {
+		t.Fatalf("wrong number of texture references: %d", refs)
+	}
+
+	area, err := ss.FrameByName("player_idle_1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ssID, frameName, found := loader.FindSpritesheetFrame(texture, area)
+
+	if !found || ssID != "player" || frameName != "player_idle_1" {
+		t.Fatalf("wrong spritesheet frame: '%s', '%s'", ssID, frameName)
+	}
+
+	if resourceType, id, _ := loader.FindResourceID(ss); resourceType !=
+		definitions.ResourceTypeSpritesheet || id != "player" {
+		t.Fatalf("wrong spritesheet ID: %s '%s'", resourceType, id)
+	}
+
+	err = loader.Release(ss)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if refs := loader.References(definitions.ResourceTypeTexture, "player"); refs != 1 {
+		t.Fatalf("the texture of the released spritesheet is referenced: %d references", refs)
+	}
+
+	// The spritesheets without the definitions
+	// are looked up in the codec spritesheet data.
+	_, err = loader.LoadSpritesheet("enemy")
+
+	if err == nil || !strings.Contains(err.Error(), "'spritesheets'") {
+		t.Fatalf("wrong error for the absent spritesheet: %v", err)
+	}
+}
+
+func TestSpritesheetDefinitionFromData(t *testing.T) {
+	def := spritesheetDefinitionFromData("player_idle", &codec.SpritesheetData{
+		Name:   "player",
+		Width:  2,
+		Height: 1,
+		Frames: []geometry.Rect{
+			geometry.R(0, 0, 16, 16),
+			geometry.R(16, 0, 32, 16),
+		},
+	})
+
+	ss, err := newSpritesheet("player_idle", def)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ss.textureID != "player" || ss.FrameCount() != 2 {
+		t.Fatalf("wrong spritesheet: texture '%s', %d frames",
+			ss.textureID, ss.FrameCount())
+	}
+
+	area, err := ss.FrameByName("player_idle_1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if area != geometry.R(16, 0, 32, 16) {
+		t.Fatalf("wrong frame area: %v", area)
+	}
+}
@@ -11,3 +11,9 @@ func Renderer() string {
 func Vendor() string {
 	return gl.GoStr(gl.GetString(gl.VENDOR))
 }
+
+// Version returns the version of OpenGL
+// supported by the renderer.
+func Version() string {
+	return gl.GoStr(gl.GetString(gl.VERSION))
+}
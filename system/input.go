@@ -0,0 +1,199 @@
+package system
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// InputSource provides the
+// state of the buttons.
+type InputSource interface {
+	ButtonPressed(button Button) bool
+}
+
+// InputFrame is the input state of a single
+// frame: the time passed since the previous frame
+// and the buttons pressed down during the frame.
+type InputFrame struct {
+	DeltaTime float64
+	Pressed   []Button
+}
+
+// inputRecordHeader is written to the
+// beginning of the input record.
+type inputRecordHeader struct {
+	Seed int64
+}
+
+var (
+	// inputSource replaces the window
+	// as the source of the button states.
+	inputSource InputSource
+)
+
+// SetInputSource makes ButtonPressed read the button
+// states from the source instead of the window.
+// If the source is nil, the window is used again.
+func SetInputSource(source InputSource) {
+	inputSource = source
+}
+
+// windowInput reads the button
+// states from the main window.
+type windowInput struct{}
+
+func (windowInput) ButtonPressed(button Button) bool {
+	return mainWindow.buttonPressed(button)
+}
+
+// InputRecorder passes the button states of the
+// other input source through and records the ones
+// that were pressed down during each frame.
+type InputRecorder struct {
+	source  InputSource
+	encoder *gob.Encoder
+	seed    int64
+	pressed map[Button]struct{}
+}
+
+// Seed returns the seed of the random
+// source written to the record.
+func (recorder *InputRecorder) Seed() int64 {
+	return recorder.seed
+}
+
+// ButtonPressed returns the button state
+// from the source and records it.
+func (recorder *InputRecorder) ButtonPressed(button Button) bool {
+	pressed := recorder.source.ButtonPressed(button)
+
+	if pressed {
+		recorder.pressed[button] = struct{}{}
+	}
+
+	return pressed
+}
+
+// EndFrame writes the state of the frame
+// to the record and starts a new frame.
+func (recorder *InputRecorder) EndFrame(deltaTime float64) error {
+	frame := InputFrame{
+		DeltaTime: deltaTime,
+		Pressed:   make([]Button, 0, len(recorder.pressed)),
+	}
+
+	for button := range recorder.pressed {
+		frame.Pressed = append(frame.Pressed, button)
+	}
+
+	sort.Slice(frame.Pressed, func(i, j int) bool {
+		return frame.Pressed[i] < frame.Pressed[j]
+	})
+
+	recorder.pressed = map[Button]struct{}{}
+
+	return recorder.encoder.Encode(frame)
+}
+
+// NewInputRecorder creates a new recorder which
+// writes the seed of the random source and the
+// frames to the writer. If the source is nil,
+// the button states are read from the window.
+func NewInputRecorder(w io.Writer, source InputSource, seed int64) (*InputRecorder, error) {
+	if w == nil {
+		return nil, fmt.Errorf("the writer is nil")
+	}
+
+	if source == nil {
+		source = windowInput{}
+	}
+
+	encoder := gob.NewEncoder(w)
+	err := encoder.Encode(inputRecordHeader{Seed: seed})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &InputRecorder{
+		source:  source,
+		encoder: encoder,
+		seed:    seed,
+		pressed: map[Button]struct{}{},
+	}, nil
+}
+
+// InputPlayer plays the recorded input back
+// frame by frame in place of the window.
+type InputPlayer struct {
+	decoder *gob.Decoder
+	seed    int64
+	frames  int
+	pressed map[Button]struct{}
+}
+
+// Seed returns the seed of the random
+// source read from the record.
+func (player *InputPlayer) Seed() int64 {
+	return player.seed
+}
+
+// Frames returns the number of
+// frames played back so far.
+func (player *InputPlayer) Frames() int {
+	return player.frames
+}
+
+// ButtonPressed returns true if the button was
+// pressed down during the current recorded frame.
+func (player *InputPlayer) ButtonPressed(button Button) bool {
+	_, pressed := player.pressed[button]
+
+	return pressed
+}
+
+// NextFrame reads the next frame from the record
+// and returns its delta time. When the record is
+// over, io.EOF is returned.
+func (player *InputPlayer) NextFrame() (float64, error) {
+	var frame InputFrame
+	err := player.decoder.Decode(&frame)
+
+	if err != nil {
+		return 0, err
+	}
+
+	player.pressed = make(map[Button]struct{}, len(frame.Pressed))
+
+	for _, button := range frame.Pressed {
+		player.pressed[button] = struct{}{}
+	}
+
+	player.frames++
+
+	return frame.DeltaTime, nil
+}
+
+// NewInputPlayer creates a new player
+// which reads the record from the reader.
+func NewInputPlayer(r io.Reader) (*InputPlayer, error) {
+	if r == nil {
+		return nil, fmt.Errorf("the reader is nil")
+	}
+
+	decoder := gob.NewDecoder(r)
+	var header inputRecordHeader
+	err := decoder.Decode(&header)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &InputPlayer{
+		decoder: decoder,
+		seed:    header.Seed,
+		pressed: map[Button]struct{}{},
+	}, nil
+}
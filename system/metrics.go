@@ -26,6 +26,14 @@ func UpdateDeltaTime() {
 	lastFrame = time.Now()
 }
 
+// SetDeltaTime sets the time (in seconds) passed
+// since the last frame. It should be used instead of
+// UpdateDeltaTime when the time is measured by an
+// external clock.
+func SetDeltaTime(dt float64) {
+	deltaTime = dt
+}
+
 // UpdateFrameRate increments the
 // frame counter and updates the FPS variable.
 // This method should be called in the end of the frame.
@@ -66,7 +66,12 @@ func InitializeWindow(title string, width, height int, fullscreen, vsync bool) e
 }
 
 // ButtonPressed returns true if the button is currently pressed down.
+// If the input source is set, the button state is read from it.
 func ButtonPressed(button Button) bool {
+	if inputSource != nil {
+		return inputSource.ButtonPressed(button)
+	}
+
 	return mainWindow.buttonPressed(button)
 }
 
@@ -78,7 +78,9 @@ func (ap *AsynchronousProcess) ProgressNotifier() <-chan int {
 }
 
 // SetProgress sets the progress
-// value for the process.
+// value for the process. If the notification
+// buffer is full, the oldest notification
+// is discarded.
 //
 // Should only be called by the
 // process initiator.
@@ -93,7 +95,21 @@ func (ap *AsynchronousProcess) SetProgress(value int) {
 	defer ap.locker.Unlock()
 
 	ap.progress = value
-	ap.progressChannel <- value
+
+	select {
+	case ap.progressChannel <- value:
+
+	default:
+		// Drop the oldest notification so the
+		// process initiator is never blocked
+		// by a slow or absent listener.
+		select {
+		case <-ap.progressChannel:
+		default:
+		}
+
+		ap.progressChannel <- value
+	}
 }
 
 // CurrentProgress returns the value
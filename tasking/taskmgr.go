@@ -140,7 +140,9 @@ func (mgr *TaskManager) Update() error {
 			return err
 		}
 
-		if !shouldContinue {
+		// The task could be stopped by
+		// itself or by destroying the manager.
+		if !shouldContinue && !tsk.stopped {
 			err = mgr.StopTask(tsk.name)
 
 			if err != nil {
@@ -155,11 +157,22 @@ func (mgr *TaskManager) Update() error {
 // Destroy stops all the tasks and
 // removes them from the task manager.
 func (mgr *TaskManager) Destroy() error {
+	// The tasks are marked as stopped so
+	// the update in progress skips them.
 	for _, tsk := range mgr.tasks {
 		tsk.stopped = true
 	}
 
-	return mgr.removeStoppedTasks()
+	for _, tsk := range mgr.startedTasks {
+		tsk.stopped = true
+	}
+
+	mgr.startedTasks = []*task{}
+	mgr.tasks = []*task{}
+	mgr.stoppedTasks = []string{}
+	mgr.afterTasks = map[string][]*task{}
+
+	return nil
 }
 
 // HasTask returns true if the task with the